@@ -0,0 +1,319 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// boardColumn is one column of a board view. Tasks holds indices into
+// Model.allTasks, in display order. Period columns hold the undated tasks of
+// the current weekly or monthly note, which have no day to move to.
+type boardColumn struct {
+	Title   string
+	Date    time.Time
+	Someday bool
+	Period  string
+	Tasks   []int
+}
+
+// todayWeekColumn is the week board's column for today, counting from
+// week_start.
+func (m Model) todayWeekColumn() int {
+	return (int(localToday().Weekday()) - int(newDateParser(m.cfg).WeekStart) + 7) % 7
+}
+
+// weekStart returns the first day of the current week.
+func (m Model) weekStart() time.Time {
+	return localToday().AddDate(0, 0, -m.todayWeekColumn())
+}
+
+// weekColumns lays out the planning board: one column per day of the
+// current week, plus a Someday column collecting undated tasks and
+// everything after the week, and a This week and This month column for each
+// periodic note source. Today's column includes overdue tasks, mirroring the
+// Today view.
+func (m Model) weekColumns() []boardColumn {
+	today := localToday()
+	start := m.weekStart()
+	cols := make([]boardColumn, 0, 8)
+	byKey := make(map[string]int)
+	for i := 0; i < 7; i++ {
+		day := start.AddDate(0, 0, i)
+		title := day.Format("Mon 02")
+		if day.Equal(today) {
+			title = "Today"
+			cols = append(cols, boardColumn{Title: title, Date: day, Tasks: slices.Clone(m.todayTasks)})
+			continue
+		}
+		byKey[day.Format("2006-01-02")] = len(cols)
+		cols = append(cols, boardColumn{Title: title, Date: day})
+	}
+
+	someday := boardColumn{Title: "Someday", Someday: true}
+	for _, g := range m.upcomingGroups {
+		key := dueDateAtLocation(g.Date, today.Location()).Format("2006-01-02")
+		if idx, ok := byKey[key]; ok {
+			cols[idx].Tasks = append(cols[idx].Tasks, g.Tasks...)
+			continue
+		}
+		someday.Tasks = append(someday.Tasks, g.Tasks...)
+	}
+	someday.Tasks = append(someday.Tasks, m.inboxTasks...)
+
+	cols = append(cols, someday)
+
+	for _, source := range m.cfg.periodicSources() {
+		col := boardColumn{Title: "This " + source.kind, Date: today, Period: source.kind}
+		for _, idx := range m.periodTasks {
+			if m.allTasks[idx].Period.Kind == source.kind {
+				col.Tasks = append(col.Tasks, idx)
+			}
+		}
+		cols = append(cols, col)
+	}
+	return cols
+}
+
+func (m Model) handleWeekAction(action string) (Model, bool) {
+	cols := m.weekColumns()
+	switch action {
+	case "left", "prev_day":
+		if m.boardCol > 0 {
+			m.boardCol--
+			m.contentCursor = 0
+		}
+	case "right", "next_day":
+		if m.boardCol < len(cols)-1 {
+			m.boardCol++
+			m.contentCursor = 0
+		}
+	case "move_left":
+		m = m.moveToWeekColumn(m.boardCol - 1)
+	case "move_right":
+		m = m.moveToWeekColumn(m.boardCol + 1)
+	default:
+		return m, false
+	}
+	return m, true
+}
+
+// moveToWeekColumn reschedules the selection (or the task under the cursor)
+// to the date of column target and keeps the cursor on the moved task.
+func (m Model) moveToWeekColumn(target int) Model {
+	cols := m.weekColumns()
+	if target < 0 || target >= len(cols) {
+		return m
+	}
+	if cols[target].Period != "" {
+		m.statusMsg = "Can't reschedule into " + cols[target].Title + "; edit the " + cols[target].Period + "ly note instead"
+		m.statusTime = time.Now()
+		return m
+	}
+	if !cols[target].Someday && cols[target].Date.Before(localToday()) {
+		m.statusMsg = "Can't reschedule into the past"
+		m.statusTime = time.Now()
+		return m
+	}
+
+	task := m.selectedTask()
+	if task == nil && len(m.selected) == 0 {
+		return m
+	}
+	var filePath string
+	var lineNumber int
+	if task != nil {
+		filePath, lineNumber = task.FilePath, task.LineNumber
+	}
+
+	if cols[target].Someday {
+		m = m.clearSelectionDueDates()
+	} else {
+		m = m.rescheduleSelection(cols[target].Date)
+	}
+	if m.err != nil {
+		return m
+	}
+	m.boardCol = target
+	m.contentCursor = 0
+	for i, idx := range m.currentViewTasks() {
+		if t := m.allTasks[idx]; t.FilePath == filePath && t.LineNumber == lineNumber {
+			m.contentCursor = i
+			break
+		}
+	}
+	return m
+}
+
+// clearSelectionDueDates moves the targets to Someday by removing their 📅
+// dates. Tasks in daily and periodic notes would fall due with their note
+// instead, so they keep their dates.
+func (m Model) clearSelectionDueDates() Model {
+	cleared, kept := 0, 0
+	for _, idx := range m.targets() {
+		task := &m.allTasks[idx]
+		if !task.NoteDate.IsZero() {
+			kept++
+			continue
+		}
+		if err := ClearDueDate(task); err != nil {
+			m.err = err
+			m.statusMsg = "Error: " + err.Error()
+			m.statusTime = time.Now()
+			return m.reload()
+		}
+		cleared++
+	}
+	m.selected = make(map[int]bool)
+	if cleared == 0 {
+		m.statusMsg = "Tasks in daily and periodic notes keep their date; reschedule them instead"
+		m.statusTime = time.Now()
+		return m
+	}
+	status := fmt.Sprintf("%d tasks → Someday", cleared)
+	if kept > 0 {
+		status += fmt.Sprintf(" · %d in daily or periodic notes kept their date", kept)
+	}
+	m.markInternalWrite(status)
+	return m.reload()
+}
+
+func (m Model) renderWeekView(maxWidth, maxHeight int) string {
+	cols := m.weekColumns()
+	today := localToday()
+	start := m.weekStart()
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(m.cfg.Theme.Accent)).
+		Bold(true)
+	title := titleStyle.Render(fmt.Sprintf("  Week · %s – %s", start.Format("Jan 02"), start.AddDate(0, 0, 6).Format("Jan 02")))
+
+	badge := func(col boardColumn) (string, string) {
+		count := len(col.Tasks)
+		limit := m.cfg.Tasks.DailyLimit
+		if col.Someday || col.Period != "" || limit <= 0 {
+			return fmt.Sprintf("%d", count), m.cfg.Theme.Muted
+		}
+		if count > limit {
+			return fmt.Sprintf("%d/%d !", count, limit), m.cfg.Theme.Overdue
+		}
+		return fmt.Sprintf("%d/%d", count, limit), m.cfg.Theme.Muted
+	}
+
+	board := m.renderBoardColumns(cols, badge, maxWidth, max(0, maxHeight-2), func(task Task) bool {
+		return isTaskOverdue(task, today)
+	})
+	return strings.Join([]string{title, "", board}, "\n")
+}
+
+// boardLayout returns the width of each of n board columns and how many
+// cards fit below their headers.
+func boardLayout(n, maxWidth, maxHeight int) (colWidth, cardsHeight int) {
+	return max(8, (maxWidth-2-(n-1))/n), max(1, maxHeight-3)
+}
+
+// boardOffset returns the first card shown in column ci; only the column
+// under the cursor scrolls.
+func (m Model) boardOffset(ci, cardsHeight int) int {
+	if ci == m.boardCol && m.contentCursor >= cardsHeight {
+		return m.contentCursor - cardsHeight + 1
+	}
+	return 0
+}
+
+// renderBoardColumns draws cols side by side. badge returns the text and
+// color shown under each column title.
+func (m Model) renderBoardColumns(cols []boardColumn, badge func(boardColumn) (string, string), maxWidth, maxHeight int, isOverdue func(Task) bool) string {
+	if len(cols) == 0 || maxHeight <= 0 {
+		return ""
+	}
+
+	colWidth, cardsHeight := boardLayout(len(cols), maxWidth, maxHeight)
+	isActive := m.focus == focusContent
+	accent := lipgloss.Color(m.cfg.Theme.Accent)
+
+	rendered := []string{"  "}
+	for ci, col := range cols {
+		activeCol := ci == m.boardCol
+		headerStyle := lipgloss.NewStyle().Width(colWidth).Bold(true).Foreground(lipgloss.Color("#cccccc"))
+		if activeCol {
+			headerStyle = headerStyle.Foreground(accent)
+		}
+		badgeText, badgeColor := badge(col)
+		badgeStyle := lipgloss.NewStyle().Width(colWidth).Foreground(lipgloss.Color(badgeColor))
+		ruleColor := lipgloss.Color("#3a3a3a")
+		if activeCol && isActive {
+			ruleColor = accent
+		}
+		ruleStyle := lipgloss.NewStyle().Foreground(ruleColor)
+
+		lines := []string{
+			headerStyle.Render(truncateText(col.Title, colWidth)),
+			badgeStyle.Render(badgeText),
+			ruleStyle.Render(strings.Repeat("─", colWidth)),
+		}
+
+		offset := m.boardOffset(ci, cardsHeight)
+		end := min(len(col.Tasks), offset+cardsHeight)
+		for i := offset; i < end; i++ {
+			taskIdx := col.Tasks[i]
+			if i == end-1 && end < len(col.Tasks) {
+				moreStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.cfg.Theme.Muted)).Italic(true)
+				lines = append(lines, moreStyle.Render(fmt.Sprintf("+%d more", len(col.Tasks)-i)))
+				break
+			}
+			cursor := isActive && activeCol && i == m.contentCursor
+			lines = append(lines, m.renderBoardCard(m.allTasks[taskIdx], colWidth, cursor, m.selected[taskIdx], isOverdue != nil && isOverdue(m.allTasks[taskIdx])))
+		}
+
+		if ci > 0 {
+			rendered = append(rendered, " ")
+		}
+		rendered = append(rendered, lipgloss.NewStyle().Width(colWidth).Render(strings.Join(lines, "\n")))
+	}
+
+	return lipgloss.JoinHorizontal(lipgloss.Top, rendered...)
+}
+
+func (m Model) renderBoardCard(task Task, width int, cursor, checked, overdue bool) string {
+	bullet := "○"
+	bulletColor := lipgloss.Color("#888888")
+	switch {
+	case task.Done:
+		bullet = "●"
+		bulletColor = lipgloss.Color(m.cfg.Theme.Done)
+	case task.Status == StatusInProgress:
+		bullet = "◐"
+		bulletColor = lipgloss.Color(m.cfg.Theme.Today)
+	case task.Status == StatusBlocked:
+		bullet = "⊘"
+		bulletColor = lipgloss.Color(m.cfg.Theme.Overdue)
+	}
+	if checked {
+		bullet = "▸"
+		bulletColor = lipgloss.Color(m.cfg.Theme.Accent)
+	} else if overdue {
+		bulletColor = lipgloss.Color(m.cfg.Theme.Overdue)
+	}
+
+	textStyle := lipgloss.NewStyle()
+	if overdue {
+		textStyle = textStyle.Foreground(lipgloss.Color(m.cfg.Theme.Overdue))
+	}
+	if task.Done {
+		textStyle = textStyle.Foreground(lipgloss.Color(m.cfg.Theme.Done)).Strikethrough(true)
+	}
+
+	desc := truncateText(task.Description, width-2)
+	line := lipgloss.NewStyle().Foreground(bulletColor).Render(bullet) + " " + textStyle.Render(desc)
+
+	cardStyle := lipgloss.NewStyle().Width(width)
+	if cursor {
+		cardStyle = cardStyle.
+			Background(lipgloss.Color("#2a2a3a")).
+			Bold(true)
+	}
+	return cardStyle.Render(line)
+}
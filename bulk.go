@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"sort"
+	"time"
+)
+
+// targets returns the tasks an action applies to: the selection when there
+// is one, otherwise the task under the cursor.
+func (m Model) targets() []int {
+	if len(m.selected) > 0 && m.activeView != viewLogbook {
+		indices := make([]int, 0, len(m.selected))
+		for idx := range m.selected {
+			indices = append(indices, idx)
+		}
+		sort.Ints(indices)
+		return indices
+	}
+	if m.activeView == viewCalendar && !m.calendarTasks && m.calendarPickFrom.IsZero() {
+		return nil
+	}
+	if tasks := m.currentViewTasks(); m.contentCursor < len(tasks) {
+		return []int{tasks[m.contentCursor]}
+	}
+	return nil
+}
+
+// targetSummary describes the targets for a confirmation prompt, e.g.
+// "7 tasks across 4 notes".
+func (m Model) targetSummary() string {
+	indices := m.targets()
+	notes := make(map[string]bool)
+	for _, idx := range indices {
+		notes[m.allTasks[idx].FilePath] = true
+	}
+	tasks, files := "tasks", "notes"
+	if len(indices) == 1 {
+		tasks = "task"
+	}
+	if len(notes) == 1 {
+		files = "note"
+	}
+	return fmt.Sprintf("%d %s across %d %s", len(indices), tasks, len(notes), files)
+}
+
+// applyToTargets runs fn on each target task, stopping at the first error,
+// and returns how many tasks it was applied to. The selection is cleared.
+func (m Model) applyToTargets(fn func(task *Task) error) (Model, int, error) {
+	count := 0
+	for _, idx := range m.targets() {
+		if err := fn(&m.allTasks[idx]); err != nil {
+			m.err = err
+			m.statusMsg = "Error: " + err.Error()
+			m.statusTime = time.Now()
+			m.selected = make(map[int]bool)
+			return m, count, err
+		}
+		count++
+	}
+	m.selected = make(map[int]bool)
+	return m, count, nil
+}
+
+func (m Model) cancelTargets() Model {
+	m, count, err := m.applyToTargets(CancelTask)
+	if err != nil {
+		return m.reload()
+	}
+	if count == 1 {
+		m.markInternalWrite("Task cancelled")
+	} else if count > 1 {
+		m.markInternalWrite(fmt.Sprintf("%d tasks cancelled", count))
+	}
+	return m.reload()
+}
+
+func (m Model) setTargetsPriority(priority int) Model {
+	m, count, err := m.applyToTargets(func(task *Task) error { return SetPriority(task, priority) })
+	if err != nil {
+		return m.reload()
+	}
+	if count == 1 {
+		m.markInternalWrite("Priority → " + priorityLabels[priority])
+	} else if count > 1 {
+		m.markInternalWrite(fmt.Sprintf("%d tasks → priority %s", count, priorityLabels[priority]))
+	}
+	return m.reload()
+}
+
+func (m Model) followUpTargets() Model {
+	var followUpDate time.Time
+	m, count, err := m.applyToTargets(func(task *Task) error {
+		date, err := CreateFollowUpTask(m.cfg, *task)
+		followUpDate = date
+		return err
+	})
+	if err != nil {
+		return m.reload()
+	}
+	if count == 1 {
+		m.markInternalWrite("Follow-up → " + followUpDate.Format("Jan 02"))
+	} else if count > 1 {
+		m.markInternalWrite(fmt.Sprintf("%d follow-ups → %s", count, followUpDate.Format("Jan 02")))
+	}
+	return m.reload()
+}
+
+// visualMotions are the actions that move the cursor without leaving visual
+// mode; any other action ends it, leaving the range selected.
+var visualMotions = map[string]bool{
+	"down": true, "up": true, "top": true, "bottom": true, "visual": true,
+}
+
+// updateVisual re-selects the range between the anchor and the cursor after
+// a motion, and leaves visual mode after anything else.
+func (m Model) updateVisual(action string) Model {
+	if !m.visual {
+		return m
+	}
+	if !visualMotions[action] || m.focus != focusContent {
+		m.visual = false
+		m.visualBase = nil
+		return m
+	}
+	tasks := m.currentViewTasks()
+	m.selected = maps.Clone(m.visualBase)
+	if m.selected == nil {
+		m.selected = make(map[int]bool)
+	}
+	lo, hi := min(m.visualAnchor, m.contentCursor), max(m.visualAnchor, m.contentCursor)
+	for i := lo; i <= hi && i < len(tasks); i++ {
+		m.selected[tasks[i]] = true
+	}
+	return m
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// calendarDay returns the day under the month grid cursor.
+func (m Model) calendarDay() time.Time {
+	if m.calendarCursor.IsZero() {
+		return localToday()
+	}
+	return m.calendarCursor
+}
+
+func (m *Model) moveCalendarCursor(days int) {
+	m.calendarCursor = m.calendarDay().AddDate(0, 0, days)
+	if m.calendarPickFrom.IsZero() {
+		m.contentCursor = 0
+		m.scrollOffset = 0
+	}
+}
+
+// moveCalendarMonth jumps by whole months, clamping the day to the length of
+// the target month so Jan 31 → Feb 28 instead of overflowing into March.
+func (m *Model) moveCalendarMonth(months int) {
+	day := m.calendarDay()
+	first := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location()).AddDate(0, months, 0)
+	last := first.AddDate(0, 1, -1).Day()
+	m.calendarCursor = first.AddDate(0, 0, min(day.Day(), last)-1)
+	if m.calendarPickFrom.IsZero() {
+		m.contentCursor = 0
+		m.scrollOffset = 0
+	}
+}
+
+// handleCalendarAction handles actions that behave differently inside the
+// month grid. It reports false for actions that should fall through to the
+// normal handlers.
+func (m Model) handleCalendarAction(action string) (Model, tea.Cmd, bool) {
+	switch action {
+	case "left", "prev_day":
+		if m.calendarTasks && m.calendarPickFrom.IsZero() {
+			return m, nil, false
+		}
+		m.moveCalendarCursor(-1)
+		return m, nil, true
+	case "right", "next_day":
+		if m.calendarTasks && m.calendarPickFrom.IsZero() {
+			return m, nil, false
+		}
+		m.moveCalendarCursor(1)
+		return m, nil, true
+	case "down":
+		if m.calendarTasks && m.calendarPickFrom.IsZero() {
+			return m, nil, false
+		}
+		m.moveCalendarCursor(7)
+		return m, nil, true
+	case "up":
+		if m.calendarTasks && m.calendarPickFrom.IsZero() {
+			return m, nil, false
+		}
+		m.moveCalendarCursor(-7)
+		return m, nil, true
+	case "prev_month":
+		m.moveCalendarMonth(-1)
+		return m, nil, true
+	case "next_month":
+		m.moveCalendarMonth(1)
+		return m, nil, true
+	}
+
+	if !m.calendarPickFrom.IsZero() {
+		switch action {
+		case "open":
+			m.mode = modeReschedule
+			m.input.Placeholder = "Date: 2006-01-02, +3d, mon, tomorrow"
+			m.input.SetValue(m.calendarDay().Format("2006-01-02"))
+			m.input.CursorEnd()
+			m.input.Focus()
+			return m, m.input.Cursor.BlinkCmd(), true
+		case "clear":
+			m.calendarCursor = m.calendarPickFrom
+			m.calendarPickFrom = time.Time{}
+			return m, nil, true
+		case "quit":
+			return m, nil, false
+		}
+		return m, nil, true
+	}
+
+	switch action {
+	case "open":
+		if m.calendarTasks {
+			return m, nil, false
+		}
+		if len(m.currentViewTasks()) > 0 {
+			m.calendarTasks = true
+			m.contentCursor = 0
+			m.scrollOffset = 0
+		}
+		return m, nil, true
+	case "reschedule":
+		if len(m.selected) > 0 || m.selectedTask() != nil {
+			m.calendarPickFrom = m.calendarDay()
+		}
+		return m, nil, true
+	case "clear":
+		if m.calendarTasks && len(m.selected) == 0 {
+			m.calendarTasks = false
+			m.contentCursor = 0
+			m.scrollOffset = 0
+			return m, nil, true
+		}
+	}
+
+	return m, nil, false
+}
+
+func (m Model) renderCalendarView(maxWidth, maxHeight int) string {
+	rows, selectedLine := m.renderCalendarRows(maxWidth)
+	rows = m.scrollRows(rows, selectedLine, maxHeight)
+	return strings.Join(rows, "\n")
+}
+
+// calendarGrid returns the first day shown in the month grid and how many
+// week rows it has.
+func (m Model) calendarGrid() (time.Time, int) {
+	cursor := m.calendarDay()
+	weekStart := newDateParser(m.cfg).WeekStart
+	first := time.Date(cursor.Year(), cursor.Month(), 1, 0, 0, 0, 0, cursor.Location())
+	start := first
+	for start.Weekday() != weekStart {
+		start = start.AddDate(0, 0, -1)
+	}
+	weeks := 0
+	for week := start; week.Month() == cursor.Month() || week.Before(first); week = week.AddDate(0, 0, 7) {
+		weeks++
+	}
+	return start, weeks
+}
+
+func calendarCellWidth(maxWidth int) int {
+	return min(10, max(5, maxWidth-2)/7)
+}
+
+func (m Model) renderCalendarRows(maxWidth int) ([]string, int) {
+	today := localToday()
+	cursor := m.calendarDay()
+	picking := !m.calendarPickFrom.IsZero()
+	accent := lipgloss.Color(m.cfg.Theme.Accent)
+	muted := lipgloss.Color(m.cfg.Theme.Muted)
+
+	arrowStyle := lipgloss.NewStyle().Foreground(accent).Bold(true)
+	titleStyle := lipgloss.NewStyle().Foreground(accent).Bold(true)
+	title := "  " + arrowStyle.Render("◀ ") + titleStyle.Render(cursor.Format("January 2006")) + arrowStyle.Render(" ▶")
+
+	rows := []string{title, ""}
+	selectedLine := -1
+
+	weekStart := newDateParser(m.cfg).WeekStart
+	cellWidth := calendarCellWidth(maxWidth)
+	headerStyle := lipgloss.NewStyle().Foreground(muted).Width(cellWidth)
+	var header strings.Builder
+	header.WriteString("  ")
+	for i := 0; i < 7; i++ {
+		header.WriteString(headerStyle.Render(" " + time.Weekday((int(weekStart) + i) % 7).String()[:3]))
+	}
+	rows = append(rows, header.String())
+
+	start, weeks := m.calendarGrid()
+	for w := 0; w < weeks; w++ {
+		week := start.AddDate(0, 0, 7*w)
+		var line strings.Builder
+		line.WriteString("  ")
+		for i := 0; i < 7; i++ {
+			line.WriteString(m.renderCalendarCell(week.AddDate(0, 0, i), cursor, today, cellWidth, picking))
+		}
+		rows = append(rows, line.String())
+	}
+	rows = append(rows, "")
+
+	source := cursor
+	if picking {
+		source = m.calendarPickFrom
+	}
+	tasks := m.currentViewTasks()
+	groupStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.cfg.Theme.Upcoming))
+	label := source.Format("Mon, Jan 02")
+	if picking {
+		label = fmt.Sprintf("%s → %s", source.Format("Jan 02"), cursor.Format("Mon, Jan 02"))
+	}
+	rows = append(rows, groupStyle.Render(fmt.Sprintf("  ── %s %s", label, strings.Repeat("─", max(0, maxWidth-lipgloss.Width(label)-6)))))
+
+	if len(tasks) == 0 {
+		emptyStyle := lipgloss.NewStyle().
+			Foreground(muted).
+			Italic(true).
+			PaddingLeft(2)
+		rows = append(rows, emptyStyle.Render("No open tasks"))
+		return rows, selectedLine
+	}
+
+	listActive := m.focus == focusContent && (m.calendarTasks || picking)
+	for i, taskIdx := range tasks {
+		task := m.allTasks[taskIdx]
+		selected := listActive && i == m.contentCursor
+		if selected {
+			selectedLine = len(rows)
+		}
+		rows = append(rows, m.renderTaskRow(task, selected, maxWidth, isTaskOverdue(task, today), m.selected[taskIdx]))
+	}
+
+	return rows, selectedLine
+}
+
+func (m Model) renderCalendarCell(day, cursor, today time.Time, width int, picking bool) string {
+	count := len(m.calendarDays[day.Format("2006-01-02")])
+	inMonth := day.Month() == cursor.Month()
+
+	left := fmt.Sprintf(" %2d", day.Day())
+	right := ""
+	if count > 0 {
+		right = fmt.Sprintf("%d", count)
+	}
+	gap := max(1, width-lipgloss.Width(left)-lipgloss.Width(right)-1)
+
+	if day.Equal(cursor) && m.focus == focusContent {
+		style := lipgloss.NewStyle().
+			Width(width).
+			Bold(true).
+			Foreground(lipgloss.Color(m.cfg.Theme.Accent)).
+			Background(lipgloss.Color("#2a2a3a"))
+		if picking {
+			style = style.
+				Foreground(lipgloss.Color("#1a1a1a")).
+				Background(lipgloss.Color(m.cfg.Theme.Accent))
+		}
+		return style.Render(left + strings.Repeat(" ", gap) + right)
+	}
+
+	dayStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#cccccc"))
+	switch {
+	case !inMonth:
+		dayStyle = dayStyle.Foreground(lipgloss.Color(m.cfg.Theme.Muted))
+	case day.Equal(today):
+		dayStyle = dayStyle.Foreground(lipgloss.Color(m.cfg.Theme.Today)).Bold(true)
+	case day.Equal(cursor):
+		dayStyle = dayStyle.Foreground(lipgloss.Color(m.cfg.Theme.Accent)).Bold(true)
+	}
+
+	countColor := m.cfg.Theme.Upcoming
+	if day.Before(today) {
+		countColor = m.cfg.Theme.Overdue
+	} else if day.Equal(today) {
+		countColor = m.cfg.Theme.Today
+	}
+	countStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(countColor)).Bold(true)
+
+	cell := dayStyle.Render(left) + strings.Repeat(" ", gap) + countStyle.Render(right)
+	return cell + strings.Repeat(" ", max(0, width-lipgloss.Width(cell)))
+}
+
+func (m Model) calendarFooterKeys() string {
+	km := m.keymap()
+	var hints []string
+	switch {
+	case !m.calendarPickFrom.IsZero():
+		hints = []string{
+			km.hint("pick day", "left", "down", "up", "right"),
+			km.hint("month", "prev_month", "next_month"),
+			km.hint("reschedule", "open"),
+			km.hint("cancel", "clear"),
+		}
+	case m.calendarTasks:
+		hints = []string{
+			km.hint("move", "down", "up"),
+			km.hint("done", "done"),
+			km.hint("pick date", "reschedule"),
+			km.hint("edit", "edit"),
+			km.hint("priority", "priority"),
+			km.hint("cancel", "cancel"),
+			km.hint("back", "clear"),
+			km.hint("help", "help"),
+		}
+	default:
+		hints = []string{
+			km.hint("day", "left", "down", "up", "right"),
+			km.hint("month", "prev_month", "next_month"),
+			km.hint("tasks", "open"),
+			km.hint("new", "new"),
+			km.hint("select", "select", "select_all"),
+			km.hint("move selected", "reschedule"),
+			km.hint("help", "help"),
+			km.hint("quit", "quit"),
+		}
+	}
+	return strings.Join(hints, "  ")
+}
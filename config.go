@@ -1,8 +1,11 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 )
@@ -11,12 +14,31 @@ type Config struct {
 	Vault VaultConfig `toml:"vault"`
 	Tasks TasksConfig `toml:"tasks"`
 	Theme ThemeConfig `toml:"theme"`
+	Open  OpenConfig  `toml:"open"`
+	// Keys maps action names to key sequences, replacing their defaults.
+	Keys map[string][]string `toml:"keys"`
+	// Warnings are problems found while loading that don't stop the app,
+	// such as an Obsidian setting it can't use.
+	Warnings []string `toml:"-"`
 }
 
 type VaultConfig struct {
 	Path            string `toml:"path"`
 	DailyNotesDir   string `toml:"daily_notes_dir"`
 	DailyNoteFormat string `toml:"daily_note_format"`
+	// TemplatePath is the note, relative to the vault, that new daily notes
+	// are created from.
+	TemplatePath string `toml:"template_path"`
+	// InboxFile is a note, relative to the vault, collecting tasks without a
+	// date. Quick-add puts tasks there when no date is given.
+	InboxFile string `toml:"inbox_file"`
+	// Weekly and monthly notes are read for tasks too when their format is
+	// set. These formats are moment.js ones, as in Obsidian, since Go
+	// layouts have no week numbers: "gggg-[W]ww", "YYYY-MM".
+	WeeklyNotesDir    string `toml:"weekly_notes_dir"`
+	WeeklyNoteFormat  string `toml:"weekly_note_format"`
+	MonthlyNotesDir   string `toml:"monthly_notes_dir"`
+	MonthlyNoteFormat string `toml:"monthly_note_format"`
 }
 
 type TasksConfig struct {
@@ -24,6 +46,43 @@ type TasksConfig struct {
 	LogbookDays    int      `toml:"logbook_days"`
 	LookaheadDays  int      `toml:"lookahead_days"`
 	ExcludeTags    []string `toml:"exclude_tags"`
+	// SectionHeadings are further headings to read tasks from, besides
+	// SectionHeading, which is where new tasks go by default.
+	SectionHeadings []string `toml:"section_headings"`
+	// SectionPattern is a regular expression; tasks under any heading that
+	// matches it are read too.
+	SectionPattern string `toml:"section_pattern"`
+	// TagSections maps a tag to the heading new tasks carrying it are
+	// written under, e.g. "#work" = "## Work".
+	TagSections map[string]string `toml:"tag_sections"`
+	// DailyLimit is the number of open tasks per day before the week board
+	// flags a column as over capacity. Zero disables the warning.
+	DailyLimit int `toml:"daily_limit"`
+	// WarnNewTags flags tags typed into inputs that aren't used anywhere
+	// in the vault, to catch #Tag vs #tag drift.
+	WarnNewTags bool `toml:"warn_new_tags"`
+	// WeekStart is the first day of the week for date expressions and the
+	// calendar grid, e.g. "monday" or "sunday".
+	WeekStart string `toml:"week_start"`
+	// Holidays are 2006-01-02 dates skipped by business-day offsets.
+	Holidays []string `toml:"holidays"`
+	// SoftWrap shows long descriptions on several lines instead of
+	// truncating them; w toggles it at runtime.
+	SoftWrap bool `toml:"soft_wrap"`
+}
+
+// OpenConfig controls how a task's note is opened outside the TUI.
+type OpenConfig struct {
+	// Editor overrides $VISUAL and $EDITOR, e.g. "nvim" or "code --wait".
+	Editor string `toml:"editor"`
+	// Opener is the command obsidian:// links are handed to; it defaults to
+	// open on macOS and xdg-open elsewhere.
+	Opener string `toml:"opener"`
+	// VaultName is the vault's name in Obsidian, if it differs from the
+	// vault folder's name.
+	VaultName string `toml:"vault_name"`
+	// AdvancedURI links to the task's line through the Advanced URI plugin.
+	AdvancedURI bool `toml:"advanced_uri"`
 }
 
 type ThemeConfig struct {
@@ -35,6 +94,30 @@ type ThemeConfig struct {
 	Muted    string `toml:"muted"`
 }
 
+// themePresets are the themes the command palette can switch to.
+var themePresets = map[string]ThemeConfig{
+	"default": {
+		Accent: "#7571F9", Overdue: "#FE5F86", Today: "#1e90ff",
+		Upcoming: "#888888", Done: "#02BF87", Muted: "#555555",
+	},
+	"nord": {
+		Accent: "#88C0D0", Overdue: "#BF616A", Today: "#81A1C1",
+		Upcoming: "#D8DEE9", Done: "#A3BE8C", Muted: "#4C566A",
+	},
+	"dracula": {
+		Accent: "#BD93F9", Overdue: "#FF5555", Today: "#8BE9FD",
+		Upcoming: "#6272A4", Done: "#50FA7B", Muted: "#44475A",
+	},
+	"gruvbox": {
+		Accent: "#D79921", Overdue: "#FB4934", Today: "#83A598",
+		Upcoming: "#A89984", Done: "#B8BB26", Muted: "#665C54",
+	},
+	"solarized": {
+		Accent: "#268BD2", Overdue: "#DC322F", Today: "#2AA198",
+		Upcoming: "#839496", Done: "#859900", Muted: "#586E75",
+	},
+}
+
 func DefaultConfig() Config {
 	return Config{
 		Vault: VaultConfig{
@@ -47,6 +130,7 @@ func DefaultConfig() Config {
 			LogbookDays:    30,
 			LookaheadDays:  14,
 			ExcludeTags:    []string{"#habit"},
+			WeekStart:      "monday",
 		},
 		Theme: ThemeConfig{
 			Accent:   "#7571F9",
@@ -59,23 +143,56 @@ func DefaultConfig() Config {
 	}
 }
 
-func LoadConfig(path string) (Config, error) {
+// LoadConfig reads config.toml, with vaultPath overriding vault.path when
+// set. The daily notes folder and format come from the vault's Obsidian
+// settings unless config.toml sets them.
+func LoadConfig(path, vaultPath string) (Config, error) {
 	cfg := DefaultConfig()
 
 	if path == "" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return cfg, nil
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, ".config", "obsidian-tasks", "config.toml")
 		}
-		path = filepath.Join(home, ".config", "obsidian-tasks", "config.toml")
 	}
 
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return cfg, nil
+	var md toml.MetaData
+	if path != "" {
+		if _, err := os.Stat(path); err == nil {
+			if md, err = toml.DecodeFile(path, &cfg); err != nil {
+				return cfg, err
+			}
+		}
+	}
+	if vaultPath != "" {
+		cfg.Vault.Path = vaultPath
 	}
+	isSet := func(key string) bool { return md.IsDefined("vault", key) }
+	applyDailyNoteSettings(&cfg, isSet)
+	applyPeriodicNoteSettings(&cfg, isSet)
 
-	if _, err := toml.DecodeFile(path, &cfg); err != nil {
-		return cfg, err
+	if _, ok := weekdayNames[strings.ToLower(cfg.Tasks.WeekStart)]; !ok {
+		return cfg, fmt.Errorf("invalid week_start %q", cfg.Tasks.WeekStart)
+	}
+	for _, h := range cfg.Tasks.Holidays {
+		if _, err := time.Parse("2006-01-02", strings.TrimSpace(h)); err != nil {
+			return cfg, fmt.Errorf("invalid holiday %q: want YYYY-MM-DD", h)
+		}
+	}
+	if _, err := newSectionMatcher(cfg.Tasks); err != nil {
+		return cfg, fmt.Errorf("invalid section_pattern: %w", err)
+	}
+	for tag, heading := range cfg.Tasks.TagSections {
+		if !isValidTag(tag) || strings.TrimSpace(heading) == "" {
+			return cfg, fmt.Errorf("invalid tag_sections entry %q = %q", tag, heading)
+		}
+	}
+	for _, source := range cfg.periodicSources() {
+		if _, err := formatMoment(localToday(), source.format, source.weekStart); err != nil {
+			return cfg, fmt.Errorf("invalid %s_note_format %q: %w", source.name, source.format, err)
+		}
+	}
+	if _, err := newKeymap(cfg.Keys); err != nil {
+		return cfg, fmt.Errorf("invalid [keys]: %w", err)
 	}
 
 	return cfg, nil
@@ -0,0 +1,365 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateParser reads the date expressions typed into reschedule, quick-add, the
+// edit form and the CLI. Relative expressions count from Today.
+type DateParser struct {
+	Today time.Time
+	// WeekStart is the first day of the week for "next week", "eow" and
+	// "next friday".
+	WeekStart time.Weekday
+	// Holidays are skipped, along with weekends, by business-day offsets.
+	// Keys are formatted as 2006-01-02.
+	Holidays map[string]bool
+}
+
+// newDateParser builds a parser for today from the configured week start and
+// holiday list. LoadConfig has already validated both.
+func newDateParser(cfg Config) DateParser {
+	p := DateParser{
+		Today:     localToday(),
+		WeekStart: time.Monday,
+		Holidays:  make(map[string]bool),
+	}
+	if wd, ok := weekdayNames[strings.ToLower(cfg.Tasks.WeekStart)]; ok {
+		p.WeekStart = wd
+	}
+	for _, h := range cfg.Tasks.Holidays {
+		if d, err := time.Parse("2006-01-02", strings.TrimSpace(h)); err == nil {
+			p.Holidays[d.Format("2006-01-02")] = true
+		}
+	}
+	return p
+}
+
+func (m Model) parseDate(input string) (time.Time, error) {
+	return newDateParser(m.cfg).Parse(input)
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+	"sun": time.Sunday, "sunday": time.Sunday,
+}
+
+// monthNames accepts English, Portuguese and Spanish month names and their
+// common abbreviations.
+var monthNames = map[string]time.Month{
+	"jan": time.January, "january": time.January, "janeiro": time.January, "ene": time.January, "enero": time.January,
+	"feb": time.February, "february": time.February, "fev": time.February, "fevereiro": time.February, "febrero": time.February,
+	"mar": time.March, "march": time.March, "março": time.March, "marco": time.March, "marzo": time.March,
+	"apr": time.April, "april": time.April, "abr": time.April, "abril": time.April,
+	"may": time.May, "mai": time.May, "maio": time.May, "mayo": time.May,
+	"jun": time.June, "june": time.June, "junho": time.June, "junio": time.June,
+	"jul": time.July, "july": time.July, "julho": time.July, "julio": time.July,
+	"aug": time.August, "august": time.August, "ago": time.August, "agosto": time.August,
+	"sep": time.September, "sept": time.September, "september": time.September, "set": time.September,
+	"setembro": time.September, "septiembre": time.September, "setiembre": time.September,
+	"oct": time.October, "october": time.October, "out": time.October, "outubro": time.October, "octubre": time.October,
+	"nov": time.November, "november": time.November, "novembro": time.November, "noviembre": time.November,
+	"dec": time.December, "december": time.December, "dez": time.December, "dezembro": time.December,
+	"dic": time.December, "diciembre": time.December,
+}
+
+var (
+	offsetRe   = regexp.MustCompile(`^([+-]?)(\d+)\s*([a-z ]+)$`)
+	inOffsetRe = regexp.MustCompile(`^in (\d+|an?|one) ([a-z ]+)$`)
+	ordinalRe  = regexp.MustCompile(`^(\d{1,2})(st|nd|rd|th|º|ª)?,?$`)
+)
+
+// offsetUnits maps the unit words accepted after a number to days, weeks,
+// months, years or business days.
+var offsetUnits = map[string]byte{
+	"d": 'd', "day": 'd', "days": 'd',
+	"w": 'w', "wk": 'w', "wks": 'w', "week": 'w', "weeks": 'w',
+	"m": 'm', "mo": 'm', "month": 'm', "months": 'm',
+	"y": 'y', "yr": 'y', "year": 'y', "years": 'y',
+	"bd": 'b', "business day": 'b', "business days": 'b', "workday": 'b', "workdays": 'b',
+}
+
+// Parse turns input into a date. It understands ISO dates, `01/02`, month
+// names (`Jan 2`, `2 de março`), weekday names with optional `next`/`this`,
+// `today`/`tomorrow`/`yesterday`, offsets such as `+3d`, `-1w`, `+2bd` and
+// `in 3 days`, and period boundaries such as `eow`, `end of month` and
+// `last day of march`.
+func (p DateParser) Parse(input string) (time.Time, error) {
+	s := strings.Join(strings.Fields(strings.ToLower(input)), " ")
+	if s == "" {
+		return time.Time{}, fmt.Errorf("empty date input")
+	}
+
+	for _, parse := range []func(string) (time.Time, bool){
+		p.parseAbsolute,
+		p.parseNamed,
+		p.parseOffset,
+		p.parseWeekday,
+		p.parseBoundary,
+	} {
+		if d, ok := parse(s); ok {
+			return d, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date: %s", input)
+}
+
+func (p DateParser) today() time.Time {
+	if p.Today.IsZero() {
+		return localToday()
+	}
+	return time.Date(p.Today.Year(), p.Today.Month(), p.Today.Day(), 0, 0, 0, 0, p.Today.Location())
+}
+
+// startOfWeek returns the first day of the week containing d.
+func (p DateParser) startOfWeek(d time.Time) time.Time {
+	return d.AddDate(0, 0, -((int(d.Weekday()) - int(p.WeekStart) + 7) % 7))
+}
+
+func (p DateParser) isBusinessDay(d time.Time) bool {
+	if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+		return false
+	}
+	return !p.Holidays[d.Format("2006-01-02")]
+}
+
+// addBusinessDays moves n business days from d, skipping weekends and
+// holidays. Zero returns d itself.
+func (p DateParser) addBusinessDays(d time.Time, n int) time.Time {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for n > 0 {
+		d = d.AddDate(0, 0, step)
+		if p.isBusinessDay(d) {
+			n--
+		}
+	}
+	return d
+}
+
+// upcoming places month/day in the current year, or the next one if that
+// date has already passed.
+func (p DateParser) upcoming(month time.Month, day int) (time.Time, bool) {
+	today := p.today()
+	d, ok := validDate(today.Year(), month, day, today.Location())
+	if ok && d.Before(today) {
+		d, ok = validDate(today.Year()+1, month, day, today.Location())
+	}
+	return d, ok
+}
+
+// validDate builds a date, rejecting days that would overflow the month.
+func validDate(year int, month time.Month, day int, loc *time.Location) (time.Time, bool) {
+	d := time.Date(year, month, day, 0, 0, 0, 0, loc)
+	return d, d.Month() == month && d.Day() == day
+}
+
+func (p DateParser) parseAbsolute(s string) (time.Time, bool) {
+	today := p.today()
+	if d, err := time.ParseInLocation("2006-01-02", s, today.Location()); err == nil {
+		return d, true
+	}
+
+	if parts := strings.Split(s, "/"); len(parts) == 2 || len(parts) == 3 {
+		month, errM := strconv.Atoi(parts[0])
+		day, errD := strconv.Atoi(parts[1])
+		if errM != nil || errD != nil || month < 1 || month > 12 {
+			return time.Time{}, false
+		}
+		if len(parts) == 3 {
+			year, err := strconv.Atoi(parts[2])
+			if err != nil || len(parts[2]) != 4 {
+				return time.Time{}, false
+			}
+			return validDate(year, time.Month(month), day, today.Location())
+		}
+		return p.upcoming(time.Month(month), day)
+	}
+
+	// Month-name dates in either order, with an optional year and the
+	// Portuguese/Spanish "de": "jan 2", "2 march 2027", "15 de março".
+	var month time.Month
+	day, year := 0, 0
+	for _, word := range strings.Fields(s) {
+		switch {
+		case word == "de" || word == "of":
+		case monthNames[strings.TrimSuffix(word, ",")] != 0 && month == 0:
+			month = monthNames[strings.TrimSuffix(word, ",")]
+		case ordinalRe.MatchString(word) && day == 0:
+			day, _ = strconv.Atoi(ordinalRe.FindStringSubmatch(word)[1])
+		case len(word) == 4 && year == 0:
+			n, err := strconv.Atoi(word)
+			if err != nil {
+				return time.Time{}, false
+			}
+			year = n
+		default:
+			return time.Time{}, false
+		}
+	}
+	if month == 0 || day == 0 {
+		return time.Time{}, false
+	}
+	if year != 0 {
+		return validDate(year, month, day, today.Location())
+	}
+	return p.upcoming(month, day)
+}
+
+func (p DateParser) parseNamed(s string) (time.Time, bool) {
+	today := p.today()
+	switch s {
+	case "today", "tod":
+		return today, true
+	case "tomorrow", "tmr", "tom":
+		return today.AddDate(0, 0, 1), true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true
+	case "next week", "nw":
+		return p.startOfWeek(today).AddDate(0, 0, 7), true
+	case "next month":
+		return time.Date(today.Year(), today.Month()+1, 1, 0, 0, 0, 0, today.Location()), true
+	case "next year":
+		return time.Date(today.Year()+1, time.January, 1, 0, 0, 0, 0, today.Location()), true
+	}
+	return time.Time{}, false
+}
+
+func (p DateParser) parseOffset(s string) (time.Time, bool) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return p.today().AddDate(0, 0, n), true
+	}
+
+	var n int
+	var unit string
+	if match := offsetRe.FindStringSubmatch(s); match != nil {
+		n, _ = strconv.Atoi(match[2])
+		if match[1] == "-" {
+			n = -n
+		}
+		unit = match[3]
+	} else if match := inOffsetRe.FindStringSubmatch(s); match != nil {
+		n = 1
+		if c, err := strconv.Atoi(match[1]); err == nil {
+			n = c
+		}
+		unit = match[2]
+	} else {
+		return time.Time{}, false
+	}
+
+	today := p.today()
+	switch offsetUnits[strings.TrimSpace(unit)] {
+	case 'd':
+		return today.AddDate(0, 0, n), true
+	case 'w':
+		return today.AddDate(0, 0, n*7), true
+	case 'm':
+		return today.AddDate(0, n, 0), true
+	case 'y':
+		return today.AddDate(n, 0, 0), true
+	case 'b':
+		return p.addBusinessDays(today, n), true
+	}
+	return time.Time{}, false
+}
+
+// parseWeekday reads `fri` as the next Friday after today, `next fri` as
+// Friday of next week and `this fri` as Friday of the current week.
+func (p DateParser) parseWeekday(s string) (time.Time, bool) {
+	today := p.today()
+	qualifier, name, found := strings.Cut(s, " ")
+	if !found {
+		qualifier, name = "", s
+	}
+	wd, ok := weekdayNames[name]
+	if !ok {
+		return time.Time{}, false
+	}
+	offset := (int(wd) - int(p.WeekStart) + 7) % 7
+
+	switch qualifier {
+	case "":
+		d := today.AddDate(0, 0, 1)
+		for d.Weekday() != wd {
+			d = d.AddDate(0, 0, 1)
+		}
+		return d, true
+	case "this":
+		return p.startOfWeek(today).AddDate(0, 0, offset), true
+	case "next":
+		return p.startOfWeek(today).AddDate(0, 0, 7+offset), true
+	}
+	return time.Time{}, false
+}
+
+// parseBoundary handles the ends of periods: `eow`, `eom`, `eoy`, `end of
+// [next] week/month/year`, and `first/last day of <month>`.
+func (p DateParser) parseBoundary(s string) (time.Time, bool) {
+	today := p.today()
+	loc := today.Location()
+	endOfMonth := func(year int, month time.Month) time.Time {
+		return time.Date(year, month+1, 0, 0, 0, 0, 0, loc)
+	}
+
+	switch s {
+	case "eow", "end of week":
+		return p.startOfWeek(today).AddDate(0, 0, 6), true
+	case "end of next week":
+		return p.startOfWeek(today).AddDate(0, 0, 13), true
+	case "eom", "end of month":
+		return endOfMonth(today.Year(), today.Month()), true
+	case "end of next month":
+		return endOfMonth(today.Year(), today.Month()+1), true
+	case "eoy", "end of year":
+		return time.Date(today.Year(), time.December, 31, 0, 0, 0, 0, loc), true
+	}
+
+	var first bool
+	var rest string
+	if r, ok := strings.CutPrefix(s, "last day of "); ok {
+		rest = r
+	} else if r, ok := strings.CutPrefix(s, "first day of "); ok {
+		first, rest = true, r
+	} else {
+		return time.Time{}, false
+	}
+
+	year, month := today.Year(), today.Month()
+	switch rest {
+	case "month", "this month":
+	case "next month":
+		month++
+	default:
+		named, ok := monthNames[rest]
+		if !ok {
+			return time.Time{}, false
+		}
+		month = named
+		var candidate time.Time
+		if first {
+			candidate = time.Date(year, month, 1, 0, 0, 0, 0, loc)
+		} else {
+			candidate = endOfMonth(year, month)
+		}
+		if candidate.Before(today) {
+			year++
+		}
+	}
+
+	if first {
+		return time.Date(year, month, 1, 0, 0, 0, 0, loc), true
+	}
+	return endOfMonth(year, month), true
+}
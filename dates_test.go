@@ -0,0 +1,142 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDateParserExpressions(t *testing.T) {
+	// Wednesday.
+	today := time.Date(2026, time.March, 18, 0, 0, 0, 0, time.Local)
+	p := DateParser{
+		Today:     today,
+		WeekStart: time.Monday,
+		Holidays:  map[string]bool{"2026-03-20": true},
+	}
+
+	cases := []struct {
+		input string
+		want  string
+	}{
+		{"2026-04-01", "2026-04-01"},
+		{"04/02", "2026-04-02"},
+		{"01/02", "2027-01-02"},
+		{"12/25/2026", "2026-12-25"},
+		{"Jan 02", "2027-01-02"},
+		{"march 20", "2026-03-20"},
+		{"2nd april", "2026-04-02"},
+		{"15 de março", "2027-03-15"},
+		{"3 de abril", "2026-04-03"},
+		{"5 enero 2027", "2027-01-05"},
+		{"today", "2026-03-18"},
+		{"tomorrow", "2026-03-19"},
+		{"fri", "2026-03-20"},
+		{"wed", "2026-03-25"},
+		{"this friday", "2026-03-20"},
+		{"next friday", "2026-03-27"},
+		{"next week", "2026-03-23"},
+		{"next month", "2026-04-01"},
+		{"+3d", "2026-03-21"},
+		{"-1w", "2026-03-11"},
+		{"+1m", "2026-04-18"},
+		{"in 3 days", "2026-03-21"},
+		{"in a week", "2026-03-25"},
+		{"in 2 months", "2026-05-18"},
+		{"+2bd", "2026-03-23"},
+		{"in 1 business day", "2026-03-19"},
+		{"eow", "2026-03-22"},
+		{"end of month", "2026-03-31"},
+		{"eom", "2026-03-31"},
+		{"end of next month", "2026-04-30"},
+		{"eoy", "2026-12-31"},
+		{"last day of february", "2027-02-28"},
+		{"last day of march", "2026-03-31"},
+		{"first day of next month", "2026-04-01"},
+		{"7", "2026-03-25"},
+	}
+	for _, c := range cases {
+		got, err := p.Parse(c.input)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", c.input, err)
+			continue
+		}
+		if got.Format("2006-01-02") != c.want {
+			t.Errorf("Parse(%q) = %s, want %s", c.input, got.Format("2006-01-02"), c.want)
+		}
+	}
+
+	for _, input := range []string{"", "someday", "feb 30", "next funday", "in 3 parsecs", "13/01"} {
+		if got, err := p.Parse(input); err == nil {
+			t.Errorf("Parse(%q) = %s, want error", input, got.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestDateParserHonorsWeekStart(t *testing.T) {
+	// Wednesday.
+	today := time.Date(2026, time.March, 18, 0, 0, 0, 0, time.Local)
+	p := DateParser{Today: today, WeekStart: time.Sunday}
+
+	cases := map[string]string{
+		"eow":         "2026-03-21",
+		"next week":   "2026-03-22",
+		"next monday": "2026-03-23",
+		"this sunday": "2026-03-15",
+	}
+	for input, want := range cases {
+		got, err := p.Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", input, err)
+		}
+		if got.Format("2006-01-02") != want {
+			t.Errorf("Parse(%q) = %s, want %s", input, got.Format("2006-01-02"), want)
+		}
+	}
+}
+
+func TestMomentFormatsTranslateToGoLayouts(t *testing.T) {
+	cases := map[string]string{
+		"YYYY-MM-DD":             "2006-01-02",
+		"YYYY/MM/YYYY-MM-DD":     "2006/01/2006-01-02",
+		"YYYY/MMMM/DD-ddd":       "2006/January/02-Mon",
+		"dddd, D MMM YY":         "Monday, 2 Jan 06",
+		"[Daily] YYYY.MM.DD":     "Daily 2006.01.02",
+		"YYYY-MM-DD [journal] A": "2006-01-02 journal PM",
+	}
+	for moment, want := range cases {
+		got, err := momentToGoLayout(moment)
+		if err != nil || got != want {
+			t.Errorf("momentToGoLayout(%q) = %q, %v; want %q", moment, got, err, want)
+		}
+	}
+	for _, moment := range []string{"gggg-[W]ww", "Do MMMM", "YYYY-MM-DD [Mon]", "[Day 1] YYYY", "YYYY-[MM"} {
+		if got, err := momentToGoLayout(moment); err == nil {
+			t.Errorf("momentToGoLayout(%q) = %q, want error", moment, got)
+		}
+	}
+}
+
+func TestFormatMomentNumbersWeeks(t *testing.T) {
+	cases := []struct {
+		day       time.Time
+		format    string
+		weekStart time.Weekday
+		want      string
+	}{
+		{time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC), "gggg-[W]ww", time.Monday, "2026-W42"},
+		{time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC), "gggg-[W]ww", time.Sunday, "2026-W42"},
+		{time.Date(2026, 12, 29, 0, 0, 0, 0, time.UTC), "GGGG-[W]WW", time.Sunday, "2026-W53"},
+		{time.Date(2026, 12, 29, 0, 0, 0, 0, time.UTC), "gggg-[W]ww", time.Sunday, "2027-W01"},
+		{time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), "YYYY/[Week] w", time.Monday, "2026/Week 2"},
+		{time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC), "YYYY-[Q]Q MMMM", time.Monday, "2026-Q4 October"},
+	}
+	for _, tc := range cases {
+		got, err := formatMoment(tc.day, tc.format, tc.weekStart)
+		if err != nil || got != tc.want {
+			t.Errorf("formatMoment(%s, %q) = %q, %v; want %q", tc.day.Format("2006-01-02"), tc.format, got, err, tc.want)
+		}
+	}
+	if _, err := formatMoment(time.Now(), "Do", time.Monday); err == nil {
+		t.Error("expected an unsupported token to be reported")
+	}
+}
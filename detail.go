@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var priorityLabels = map[int]string{
+	PriorityHighest: "Highest",
+	PriorityHigh:    "High",
+	PriorityMedium:  "Medium",
+	PriorityLow:     "Low",
+	PriorityLowest:  "Lowest",
+	PriorityNone:    "None",
+}
+
+func statusLabel(t Task) string {
+	switch t.StatusChar() {
+	case StatusDone:
+		return "Done"
+	case StatusCancelled:
+		return "Cancelled"
+	case StatusInProgress:
+		return "In progress"
+	case StatusBlocked:
+		return "Blocked"
+	}
+	return "Open"
+}
+
+func formatDetailDate(d time.Time) string {
+	if d.IsZero() {
+		return "—"
+	}
+	return d.Format("Mon, Jan 02 2006")
+}
+
+// detailFields lists every parsed field of t as label/value pairs, in the
+// order shown by the detail pane.
+func (m Model) detailFields(t Task) [][2]string {
+	priority := priorityLabels[t.Priority]
+	if emoji, ok := priorityEmojis[t.Priority]; ok {
+		priority = emoji + " " + priority
+	}
+	tags := strings.Join(t.Tags, " ")
+	if tags == "" {
+		tags = "—"
+	}
+
+	due := formatDetailDate(t.DueDate)
+	if !t.Period.End.IsZero() {
+		due = t.Period.label()
+	}
+
+	fields := [][2]string{
+		{"Description", t.Description},
+		{"Status", statusLabel(t)},
+		{"Priority", priority},
+		{"Tags", tags},
+		{"Due", due},
+		{"Scheduled", formatDetailDate(t.ScheduledDate)},
+		{"Start", formatDetailDate(t.StartDate)},
+	}
+	if t.Recurrence != "" {
+		fields = append(fields, [2]string{"Recurrence", t.Recurrence})
+	}
+	if t.Done {
+		fields = append(fields, [2]string{"Completed", formatDetailDate(t.CompletionDate)})
+	}
+	if t.Cancelled {
+		fields = append(fields, [2]string{"Cancelled", formatDetailDate(t.CancelledDate)})
+	}
+
+	note := t.FilePath
+	if rel, err := filepath.Rel(m.cfg.Vault.Path, t.FilePath); err == nil && !strings.HasPrefix(rel, "..") {
+		note = rel
+	}
+	heading := t.Heading
+	if heading == "" {
+		heading = "—"
+	}
+	fields = append(fields,
+		[2]string{"Note", note},
+		[2]string{"Line", fmt.Sprintf("%d", t.LineNumber)},
+		[2]string{"Heading", heading},
+	)
+	if t.Section != "" && t.Section != t.Heading {
+		fields = append(fields, [2]string{"Section", t.Section})
+	}
+	return fields
+}
+
+// loadDetailContext reads the context of the selected task once the
+// selection moves to it, so the detail pane doesn't read the note on every
+// render.
+func (m Model) loadDetailContext() Model {
+	task := m.selectedTask()
+	if !m.showDetail || task == nil {
+		m.detailContext, m.detailContextFor = nil, taskRef{}
+		return m
+	}
+	ref := taskRef{task.FilePath, task.LineNumber}
+	if ref != m.detailContextFor {
+		m.detailContext, _ = TaskContext(*task, 3)
+		m.detailContextFor = ref
+	}
+	return m
+}
+
+func (m Model) renderDetail(width, height int) string {
+	accent := lipgloss.Color(m.cfg.Theme.Accent)
+	muted := lipgloss.Color(m.cfg.Theme.Muted)
+	innerWidth := max(10, width-4)
+
+	titleStyle := lipgloss.NewStyle().Foreground(accent).Bold(true)
+	rows := []string{titleStyle.Render("  Details"), ""}
+
+	task := m.selectedTask()
+	if task == nil {
+		emptyStyle := lipgloss.NewStyle().
+			Foreground(muted).
+			Italic(true).
+			PaddingLeft(2)
+		rows = append(rows, emptyStyle.Render("No task selected"))
+	} else {
+		labelWidth := 14
+		labelStyle := lipgloss.NewStyle().Foreground(muted).Width(labelWidth).PaddingLeft(2)
+		valueStyle := lipgloss.NewStyle().Width(max(8, innerWidth-labelWidth))
+		for _, f := range m.detailFields(*task) {
+			rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, labelStyle.Render(f[0]), valueStyle.Render(f[1])))
+		}
+
+		context := m.detailContext
+		if m.detailContextFor != (taskRef{task.FilePath, task.LineNumber}) {
+			context = nil
+		}
+		if len(context) > 0 {
+			ruleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.cfg.Theme.Upcoming))
+			rows = append(rows, "", ruleStyle.Render("  ── Context "+strings.Repeat("─", max(0, innerWidth-12))))
+			contextStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#999999")).PaddingLeft(2)
+			for _, line := range context {
+				rows = append(rows, contextStyle.Render(truncateText(strings.ReplaceAll(line, "\t", "  "), innerWidth-2)))
+			}
+		}
+	}
+
+	body := strings.Join(rows, "\n")
+	if lines := strings.Split(body, "\n"); len(lines) > height {
+		body = strings.Join(lines[:height], "\n")
+	}
+
+	paneStyle := lipgloss.NewStyle().
+		Border(subtleBorder).
+		BorderForeground(lipgloss.Color("#3a3a3a")).
+		Width(width).
+		Height(height)
+
+	return paneStyle.Render(body)
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	formDescription = iota
+	formTags
+	formPriority
+	formDue
+	formScheduled
+	formStart
+	formRecurrence
+)
+
+var formLabels = []string{
+	formDescription: "Description",
+	formTags:        "Tags",
+	formPriority:    "Priority",
+	formDue:         "Due",
+	formScheduled:   "Scheduled",
+	formStart:       "Start",
+	formRecurrence:  "Recurrence",
+}
+
+var formPlaceholders = []string{
+	formDescription: "What needs doing",
+	formTags:        "#work #ops",
+	formPriority:    "1-5, high, low, 0 for none",
+	formDue:         "2006-01-02, +3d, fri, tomorrow",
+	formScheduled:   "empty for none",
+	formStart:       "empty for none",
+	formRecurrence:  "every week on Monday",
+}
+
+// priorityNames maps the words and shorthands accepted wherever a priority is
+// typed in to their priority level.
+var priorityNames = map[string]int{
+	"1": PriorityHighest, "p1": PriorityHighest, "highest": PriorityHighest,
+	"2": PriorityHigh, "p2": PriorityHigh, "high": PriorityHigh,
+	"3": PriorityMedium, "p3": PriorityMedium, "medium": PriorityMedium,
+	"4": PriorityLow, "p4": PriorityLow, "low": PriorityLow,
+	"5": PriorityLowest, "p5": PriorityLowest, "lowest": PriorityLowest,
+	"0": PriorityNone, "": PriorityNone, "none": PriorityNone,
+}
+
+func parsePriority(input string) (int, error) {
+	input = strings.TrimSpace(input)
+	if p, ok := emojiToPriority[input]; ok {
+		return p, nil
+	}
+	if p, ok := priorityNames[strings.ToLower(input)]; ok {
+		return p, nil
+	}
+	return PriorityNone, fmt.Errorf("unknown priority: %s", input)
+}
+
+// parseTagList splits a space or comma separated list of tags, adding the
+// leading # where it was left out.
+func parseTagList(input string) []string {
+	var tags []string
+	for _, field := range strings.FieldsFunc(input, func(r rune) bool { return r == ' ' || r == ',' }) {
+		if !strings.HasPrefix(field, "#") {
+			field = "#" + field
+		}
+		if field != "#" {
+			tags = append(tags, field)
+		}
+	}
+	return tags
+}
+
+func formatInputDate(d time.Time) string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format("2006-01-02")
+}
+
+func (m Model) openEditForm(task Task) (Model, tea.Cmd) {
+	values := []string{
+		formDescription: task.Description,
+		formTags:        strings.Join(task.Tags, " "),
+		formPriority:    "",
+		formDue:         formatInputDate(task.DueDate),
+		formScheduled:   formatInputDate(task.ScheduledDate),
+		formStart:       formatInputDate(task.StartDate),
+		formRecurrence:  task.Recurrence,
+	}
+	if task.Priority != PriorityNone {
+		values[formPriority] = strings.ToLower(priorityLabels[task.Priority])
+	}
+
+	m.form = make([]textinput.Model, len(formLabels))
+	for i := range m.form {
+		ti := textinput.New()
+		ti.Prompt = ""
+		ti.CharLimit = 256
+		ti.Width = 48
+		ti.Placeholder = formPlaceholders[i]
+		ti.SetValue(values[i])
+		m.form[i] = ti
+	}
+	m.formFocus = formDescription
+	m.formErr = ""
+	m.mode = modeForm
+	m.form[m.formFocus].Focus()
+	return m, m.form[m.formFocus].Cursor.BlinkCmd()
+}
+
+func (m *Model) focusFormField(idx int) tea.Cmd {
+	m.form[m.formFocus].Blur()
+	m.formFocus = (idx + len(m.form)) % len(m.form)
+	m.form[m.formFocus].CursorEnd()
+	return m.form[m.formFocus].Focus()
+}
+
+func (m Model) handleForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.mode = modeNormal
+		m.form = nil
+		return m, nil
+	case "tab":
+		if m.formFocus == formTags && m.completeTag(&m.form[formTags]) {
+			return m, nil
+		}
+		m.tagCycle = nil
+		return m, m.focusFormField(m.formFocus + 1)
+	case "down":
+		m.tagCycle = nil
+		return m, m.focusFormField(m.formFocus + 1)
+	case "shift+tab", "up":
+		m.tagCycle = nil
+		return m, m.focusFormField(m.formFocus - 1)
+	case "enter":
+		m.tagCycle = nil
+		return m.submitForm()
+	}
+	m.tagCycle = nil
+
+	var cmd tea.Cmd
+	m.form[m.formFocus], cmd = m.form[m.formFocus].Update(msg)
+	return m, cmd
+}
+
+// submitForm validates every field and, only if all of them parse, rewrites
+// the task line in a single write.
+func (m Model) submitForm() (tea.Model, tea.Cmd) {
+	task := m.selectedTask()
+	if task == nil {
+		m.mode = modeNormal
+		m.form = nil
+		return m, nil
+	}
+
+	edited := *task
+	edited.Description = strings.TrimSpace(m.form[formDescription].Value())
+	if edited.Description == "" {
+		return m.formError(formDescription, "description is required")
+	}
+	edited.Tags = parseTagList(m.form[formTags].Value())
+
+	priority, err := parsePriority(m.form[formPriority].Value())
+	if err != nil {
+		return m.formError(formPriority, err.Error())
+	}
+	edited.Priority = priority
+
+	dates := []struct {
+		field int
+		dest  *time.Time
+	}{
+		{formDue, &edited.DueDate},
+		{formScheduled, &edited.ScheduledDate},
+		{formStart, &edited.StartDate},
+	}
+	for _, d := range dates {
+		value := strings.TrimSpace(m.form[d.field].Value())
+		if value == "" {
+			*d.dest = time.Time{}
+			continue
+		}
+		parsed, err := m.parseDate(value)
+		if err != nil {
+			return m.formError(d.field, "invalid date: "+value)
+		}
+		*d.dest = parsed
+	}
+	// A task in a daily or periodic note is due with its note once its own
+	// date is gone, so an empty Due can't make it undated.
+	if edited.DueDate.IsZero() && !task.NoteDate.IsZero() {
+		return m.formError(formDue, "tasks in daily and periodic notes are due with their note; pick another date")
+	}
+	// Leaving the due date as shown keeps a task due with its note that way.
+	if !edited.DueDate.Equal(task.DueDate) {
+		edited.HasDueDate = !edited.DueDate.IsZero()
+	}
+
+	edited.Recurrence = strings.TrimSpace(m.form[formRecurrence].Value())
+	if edited.Recurrence != "" && !strings.HasPrefix(strings.ToLower(edited.Recurrence), "every") {
+		return m.formError(formRecurrence, `recurrence must start with "every"`)
+	}
+
+	m.mode = modeNormal
+	m.form = nil
+	if err := UpdateTaskLine(task, formatTaskLine(edited)); err != nil {
+		m.err = err
+		m.statusMsg = "Error: " + err.Error()
+		m.statusTime = time.Now()
+		return m, nil
+	}
+	m.markInternalWrite("Task updated")
+	return m.reload(), nil
+}
+
+func (m Model) formError(field int, msg string) (tea.Model, tea.Cmd) {
+	m.formErr = formLabels[field] + ": " + msg
+	return m, m.focusFormField(field)
+}
+
+func (m Model) renderForm() string {
+	accent := lipgloss.Color(m.cfg.Theme.Accent)
+	titleStyle := lipgloss.NewStyle().Foreground(accent).Bold(true)
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#999999")).Width(13)
+	activeLabel := labelStyle.Foreground(accent).Bold(true)
+
+	rows := []string{titleStyle.Render("Edit task"), ""}
+	for i, input := range m.form {
+		style := labelStyle
+		if i == m.formFocus {
+			style = activeLabel
+		}
+		rows = append(rows, style.Render(formLabels[i])+input.View())
+		if i == formTags && i == m.formFocus {
+			if line := m.renderTagSuggestions(input); line != "" {
+				rows = append(rows, strings.Repeat(" ", 10)+line)
+			}
+			if warning := m.newTagWarning(m.typedTags(input)); warning != "" {
+				warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.cfg.Theme.Overdue))
+				rows = append(rows, strings.Repeat(" ", 13)+warnStyle.Render("⚠ "+warning))
+			}
+		}
+	}
+
+	rows = append(rows, "")
+	if m.formErr != "" {
+		errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.cfg.Theme.Overdue)).Bold(true)
+		rows = append(rows, errStyle.Render(m.formErr))
+	}
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.cfg.Theme.Muted))
+	rows = append(rows, hintStyle.Render("tab/shift+tab move (tab completes #tags)  enter save  esc cancel"))
+
+	style := lipgloss.NewStyle().
+		Border(subtleBorder).
+		BorderForeground(accent).
+		Padding(1, 3).
+		Width(70)
+
+	return lipgloss.Place(m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		style.Render(strings.Join(rows, "\n")))
+}
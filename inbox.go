@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// taskRef finds a task again after a reload. Triage only edits lines in
+// place, so the line number stays put.
+type taskRef struct {
+	FilePath   string
+	LineNumber int
+}
+
+func (m Model) findTask(ref taskRef) int {
+	for i, t := range m.allTasks {
+		if t.FilePath == ref.FilePath && t.LineNumber == ref.LineNumber {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m Model) renderInboxView(maxWidth, maxHeight int) string {
+	rows, selectedLine, _ := m.inboxLayout(maxWidth)
+	rows = m.scrollRows(rows, selectedLine, maxHeight)
+	return strings.Join(rows, "\n")
+}
+
+// inboxLayout is todayLayout for the Inbox view.
+func (m Model) inboxLayout(maxWidth int) ([]string, int, []int) {
+	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.cfg.Theme.Accent)).Bold(true)
+	rows := []string{titleStyle.Render("  Inbox · no date"), ""}
+	selectedLine := -1
+
+	if len(m.inboxTasks) == 0 {
+		emptyStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(m.cfg.Theme.Muted)).
+			Italic(true).
+			PaddingLeft(2)
+		empty := "Nothing without a date"
+		if m.cfg.Vault.InboxFile == "" {
+			empty = "Set inbox_file to collect tasks without a date"
+		}
+		rows = append(rows, emptyStyle.Render(empty))
+		return rows, selectedLine, nil
+	}
+
+	isActive := m.focus == focusContent
+	selectedTaskIdx := -1
+	if isActive && len(m.inboxTasks) > m.contentCursor {
+		selectedTaskIdx = m.inboxTasks[m.contentCursor]
+	}
+	taskRows, taskSelectedLine, taskLines := m.renderPrioritySeparatedRows(m.inboxTasks, maxWidth, isActive, selectedTaskIdx, func(Task) bool { return false })
+	if taskSelectedLine >= 0 {
+		selectedLine = len(rows) + taskSelectedLine
+	}
+	lineTasks := append(blankLines(len(rows)), taskLines...)
+	return append(rows, taskRows...), selectedLine, lineTasks
+}
+
+// startTriage walks through the inbox one task at a time, asking for a date,
+// priority or tags for each.
+func (m Model) startTriage() (Model, tea.Cmd) {
+	if len(m.inboxTasks) == 0 {
+		m.statusMsg = "Inbox is empty"
+		m.statusTime = time.Now()
+		return m, nil
+	}
+	m.triageQueue = nil
+	for _, idx := range m.inboxTasks {
+		t := m.allTasks[idx]
+		m.triageQueue = append(m.triageQueue, taskRef{t.FilePath, t.LineNumber})
+	}
+	m.setActiveView(viewInbox)
+	m.focus = focusContent
+	m = m.nextTriage()
+	return m, m.input.Cursor.BlinkCmd()
+}
+
+// nextTriage puts the cursor on the task at the head of the queue, skipping
+// ones that are gone or got a date meanwhile, and prompts for it.
+func (m Model) nextTriage() Model {
+	for len(m.triageQueue) > 0 {
+		idx := m.findTask(m.triageQueue[0])
+		if idx >= 0 && m.allTasks[idx].IsUndated() && !m.allTasks[idx].IsCompleted() {
+			for pos, inboxIdx := range m.inboxTasks {
+				if inboxIdx == idx {
+					m.contentCursor = pos
+				}
+			}
+			m.mode = modeTriage
+			m.input.Placeholder = "Date, priority, #tags: fri !! #errand · Enter to skip, Esc to stop"
+			m.input.SetValue("")
+			m.input.Focus()
+			return m
+		}
+		m.triageQueue = m.triageQueue[1:]
+	}
+	m.mode = modeNormal
+	m.input.Blur()
+	m.statusMsg = "Inbox triaged"
+	m.statusTime = time.Now()
+	return m
+}
+
+// triageAnswer applies the quick-add date, priority and tags in value to the
+// task being triaged and moves on. An empty answer skips the task.
+func (m Model) triageAnswer(value string) Model {
+	if len(m.triageQueue) == 0 {
+		m.mode = modeNormal
+		return m
+	}
+	if strings.TrimSpace(value) != "" {
+		answer := parseQuickAdd(value, time.Time{}, m.parseDate)
+		// Unlike quick-add, a bare date needs no "due" before it.
+		if d, err := m.parseDate(answer.Description); err == nil && answer.Description != "" {
+			answer.Description, answer.DueDate, answer.HasDueDate = "", d, true
+		}
+		if answer.Description != "" {
+			m.statusMsg = "Not a date, priority or tag: " + answer.Description
+			m.statusTime = time.Now()
+			m.input.SetValue(value)
+			m.input.Focus()
+			return m
+		}
+		idx := m.findTask(m.triageQueue[0])
+		if idx >= 0 {
+			task := &m.allTasks[idx]
+			if err := applyTriage(task, answer); err != nil {
+				m.err = err
+				m.statusMsg = "Error: " + err.Error()
+				m.statusTime = time.Now()
+				m.mode = modeNormal
+				m.triageQueue = nil
+				return m.reload()
+			}
+			m.markInternalWrite("Triaged " + task.Description)
+			m = m.reload()
+		}
+	}
+	m.triageQueue = m.triageQueue[1:]
+	return m.nextTriage()
+}
+
+func applyTriage(task *Task, answer Task) error {
+	if !answer.DueDate.IsZero() {
+		if err := RescheduleTask(task, answer.DueDate); err != nil {
+			return err
+		}
+	}
+	if answer.Priority != PriorityNone {
+		if err := SetPriority(task, answer.Priority); err != nil {
+			return err
+		}
+	}
+	if len(answer.Tags) > 0 {
+		if _, err := EditTaskTags(task, answer.Tags, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// triagePrompt labels the triage input with the task and how many are left.
+func (m Model) triagePrompt(maxWidth int) string {
+	desc := ""
+	if len(m.triageQueue) > 0 {
+		if idx := m.findTask(m.triageQueue[0]); idx >= 0 {
+			desc = m.allTasks[idx].Description
+		}
+	}
+	return fmt.Sprintf(" Triage (%d left) %s: ", len(m.triageQueue), truncateText(desc, max(10, maxWidth)))
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// boardStatuses lists the kanban columns in order, left to right.
+var boardStatuses = []struct {
+	title  string
+	status rune
+}{
+	{"Todo", StatusTodo},
+	{"In progress", StatusInProgress},
+	{"Blocked", StatusBlocked},
+	{"Done", StatusDone},
+}
+
+// statusColumns groups open tasks by checkbox status. The Done column only
+// holds tasks completed today so it doesn't grow into a second logbook.
+func (m Model) statusColumns() []boardColumn {
+	today := localToday()
+	cols := make([]boardColumn, len(boardStatuses))
+	byStatus := make(map[rune]int)
+	for i, s := range boardStatuses {
+		cols[i].Title = s.title
+		byStatus[s.status] = i
+	}
+
+	for i, t := range m.allTasks {
+		if t.Cancelled || !m.matchesFilter(t) {
+			continue
+		}
+		if t.Done && !dueDateAtLocation(t.ClosedDate(), today.Location()).Equal(today) {
+			continue
+		}
+		col, ok := byStatus[t.StatusChar()]
+		if !ok {
+			continue
+		}
+		cols[col].Tasks = append(cols[col].Tasks, i)
+	}
+
+	for _, col := range cols {
+		tasks := col.Tasks
+		sort.SliceStable(tasks, func(a, b int) bool {
+			ta, tb := m.allTasks[tasks[a]], m.allTasks[tasks[b]]
+			if !ta.DueDate.Equal(tb.DueDate) {
+				return ta.DueDate.Before(tb.DueDate)
+			}
+			return ta.Priority < tb.Priority
+		})
+	}
+
+	return cols
+}
+
+func (m Model) handleBoardAction(action string) (Model, bool) {
+	switch action {
+	case "left", "prev_day":
+		if m.boardCol > 0 {
+			m.boardCol--
+			m.contentCursor = 0
+		}
+	case "right", "next_day":
+		if m.boardCol < len(boardStatuses)-1 {
+			m.boardCol++
+			m.contentCursor = 0
+		}
+	case "move_left":
+		m = m.moveToStatusColumn(m.boardCol - 1)
+	case "move_right":
+		m = m.moveToStatusColumn(m.boardCol + 1)
+	default:
+		return m, false
+	}
+	return m, true
+}
+
+// moveToStatusColumn changes the checkbox of the selection (or the task under
+// the cursor) to the status of column target.
+func (m Model) moveToStatusColumn(target int) Model {
+	if target < 0 || target >= len(boardStatuses) {
+		return m
+	}
+	status := boardStatuses[target].status
+
+	indices := m.targets()
+	if len(indices) == 0 {
+		return m
+	}
+
+	var filePath string
+	var lineNumber int
+	count := 0
+	for _, idx := range indices {
+		task := &m.allTasks[idx]
+		filePath, lineNumber = task.FilePath, task.LineNumber
+		if err := SetStatus(task, status); err != nil {
+			m.err = err
+			m.statusMsg = "Error: " + err.Error()
+			m.statusTime = time.Now()
+			return m
+		}
+		count++
+	}
+
+	m.selected = make(map[int]bool)
+	if count == 1 {
+		m.markInternalWrite("Moved → " + boardStatuses[target].title)
+	} else {
+		m.markInternalWrite(fmt.Sprintf("%d tasks → %s", count, boardStatuses[target].title))
+	}
+	m = m.reload()
+	m.boardCol = target
+	m.contentCursor = 0
+	for i, idx := range m.currentViewTasks() {
+		if t := m.allTasks[idx]; t.FilePath == filePath && t.LineNumber == lineNumber {
+			m.contentCursor = i
+			break
+		}
+	}
+	return m
+}
+
+func (m Model) renderBoardView(maxWidth, maxHeight int) string {
+	today := localToday()
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(m.cfg.Theme.Accent)).
+		Bold(true)
+	title := titleStyle.Render("  Board")
+
+	badge := func(col boardColumn) (string, string) {
+		return fmt.Sprintf("%d", len(col.Tasks)), m.cfg.Theme.Muted
+	}
+	board := m.renderBoardColumns(m.statusColumns(), badge, maxWidth, max(0, maxHeight-2), func(task Task) bool {
+		return !task.IsCompleted() && isTaskOverdue(task, today)
+	})
+	return strings.Join([]string{title, "", board}, "\n")
+}
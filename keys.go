@@ -0,0 +1,419 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// keySequenceTimeout is how long a key that is both a binding and the start
+// of a longer sequence waits for the next key before firing on its own.
+const keySequenceTimeout = 600 * time.Millisecond
+
+// Key scopes: actions only collide with other actions in the same scope.
+const (
+	scopeNormal = iota
+	scopePriority
+	scopeConfirm
+)
+
+type keyAction struct {
+	name  string
+	scope int
+	keys  []string
+	desc  string
+}
+
+// keyActions lists every bindable action with its default keys. The names
+// are the keys of the `[keys]` config section.
+var keyActions = []keyAction{
+	{"quit", scopeNormal, []string{"q"}, "Quit"},
+	{"view_today", scopeNormal, []string{"1"}, "Show Today"},
+	{"view_upcoming", scopeNormal, []string{"2"}, "Show Upcoming"},
+	{"view_logbook", scopeNormal, []string{"3"}, "Show Logbook"},
+	{"view_calendar", scopeNormal, []string{"4"}, "Show Calendar"},
+	{"view_week", scopeNormal, []string{"5"}, "Show the week board"},
+	{"view_board", scopeNormal, []string{"6"}, "Show the kanban board"},
+	{"view_inbox", scopeNormal, []string{"7"}, "Show tasks without a date"},
+	{"toggle_focus", scopeNormal, []string{"tab"}, "Toggle sidebar/content focus"},
+	{"left", scopeNormal, []string{"h"}, "Sidebar / previous column"},
+	{"right", scopeNormal, []string{"l"}, "Content / next column"},
+	{"down", scopeNormal, []string{"j", "down"}, "Move down"},
+	{"up", scopeNormal, []string{"k", "up"}, "Move up"},
+	{"top", scopeNormal, []string{"gg"}, "Jump to the first task"},
+	{"bottom", scopeNormal, []string{"G"}, "Jump to the last task"},
+	{"prev_day", scopeNormal, []string{"left"}, "Logbook: previous day"},
+	{"next_day", scopeNormal, []string{"right"}, "Logbook: next day"},
+	{"prev_month", scopeNormal, []string{"["}, "Calendar: previous month"},
+	{"next_month", scopeNormal, []string{"]"}, "Calendar: next month"},
+	{"move_left", scopeNormal, []string{"H", "shift+left"}, "Move task to the previous column"},
+	{"move_right", scopeNormal, []string{"L", "shift+right"}, "Move task to the next column"},
+	{"open", scopeNormal, []string{"enter"}, "Select view or toggle done"},
+	{"select", scopeNormal, []string{"space"}, "Toggle selection"},
+	{"select_all", scopeNormal, []string{"v"}, "Select/deselect all"},
+	{"visual", scopeNormal, []string{"V"}, "Select a range with j/k"},
+	{"done", scopeNormal, []string{"d"}, "Toggle done/reopen"},
+	{"follow_up", scopeNormal, []string{"f", "F"}, "Create follow-up for tomorrow"},
+	{"cancel", scopeNormal, []string{"D"}, "Cancel task"},
+	{"new", scopeNormal, []string{"n"}, "New task"},
+	{"open_editor", scopeNormal, []string{"o"}, "Open the task's note in $EDITOR"},
+	{"open_obsidian", scopeNormal, []string{"O"}, "Open the task's note in Obsidian"},
+	{"edit", scopeNormal, []string{"e"}, "Edit description"},
+	{"edit_form", scopeNormal, []string{"E"}, "Edit all fields"},
+	{"priority", scopeNormal, []string{"p"}, "Set priority"},
+	{"reschedule", scopeNormal, []string{"s"}, "Reschedule"},
+	{"triage", scopeNormal, []string{"T"}, "Triage the inbox one task at a time"},
+	{"filter", scopeNormal, []string{"/"}, "Filter by text"},
+	{"clear", scopeNormal, []string{"esc"}, "Clear selection or filter"},
+	{"help", scopeNormal, []string{"?"}, "Show help"},
+	{"details", scopeNormal, []string{"i"}, "Toggle task detail pane"},
+	{"separators", scopeNormal, []string{"t"}, "Toggle priority separators"},
+	{"wrap", scopeNormal, []string{"w"}, "Toggle soft wrap of long descriptions"},
+	{"tag_add", scopeNormal, []string{"+"}, "Add tags"},
+	{"tag_remove", scopeNormal, []string{"-"}, "Remove tags"},
+	{"reload", scopeNormal, []string{"r"}, "Reload from files"},
+	{"palette", scopeNormal, []string{":", "ctrl+p"}, "Open the command palette"},
+
+	{"priority_highest", scopePriority, []string{"1"}, "Highest priority"},
+	{"priority_high", scopePriority, []string{"2"}, "High priority"},
+	{"priority_medium", scopePriority, []string{"3"}, "Medium priority"},
+	{"priority_low", scopePriority, []string{"4"}, "Low priority"},
+	{"priority_lowest", scopePriority, []string{"5"}, "Lowest priority"},
+	{"priority_none", scopePriority, []string{"0"}, "No priority"},
+	{"priority_dismiss", scopePriority, []string{"esc", "q"}, "Close the priority prompt"},
+
+	{"confirm_yes", scopeConfirm, []string{"y", "Y"}, "Confirm"},
+	{"confirm_no", scopeConfirm, []string{"n", "N", "esc", "q"}, "Dismiss"},
+}
+
+// namedKeys are the multi-letter key names that stand for a single key.
+var namedKeys = map[string]bool{
+	"enter": true, "esc": true, "tab": true, "space": true, "backspace": true,
+	"delete": true, "insert": true, "up": true, "down": true, "left": true,
+	"right": true, "home": true, "end": true, "pgup": true, "pgdown": true,
+}
+
+// Keymap maps each action to its key sequences. A sequence is one or more
+// key names as reported by tea.KeyMsg.String(), with " " spelled "space".
+type Keymap map[string][][]string
+
+var defaultKeymap, _ = newKeymap(nil)
+
+// newKeymap applies the `[keys]` overrides on top of the defaults. An
+// override replaces every default key of its action. It fails on unknown
+// action names, on sequences bound to two actions in the same scope, on
+// multi-key sequences in the prompts, which only read single keys, and on
+// ctrl+c, which quits whatever the bindings.
+func newKeymap(overrides map[string][]string) (Keymap, error) {
+	km := make(Keymap)
+	scopes := make(map[string]int)
+	for _, a := range keyActions {
+		scopes[a.name] = a.scope
+		for _, k := range a.keys {
+			km[a.name] = append(km[a.name], parseKeySequence(k))
+		}
+	}
+
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := scopes[name]; !ok {
+			return nil, fmt.Errorf("unknown key action %q", name)
+		}
+		km[name] = nil
+		for _, k := range overrides[name] {
+			seq := parseKeySequence(k)
+			if slices.Contains(seq, "ctrl+c") {
+				return nil, fmt.Errorf("key %q of %s: ctrl+c always quits", k, name)
+			}
+			if len(seq) > 1 && scopes[name] != scopeNormal {
+				return nil, fmt.Errorf("key %q of %s: prompts only take single keys", k, name)
+			}
+			if len(seq) > 0 {
+				km[name] = append(km[name], seq)
+			}
+		}
+	}
+
+	seen := make(map[string]string)
+	for _, a := range keyActions {
+		for _, seq := range km[a.name] {
+			id := fmt.Sprintf("%d:%s", a.scope, strings.Join(seq, " "))
+			if other, ok := seen[id]; ok {
+				return nil, fmt.Errorf("key %q is bound to both %s and %s", formatKeySequence(seq), other, a.name)
+			}
+			seen[id] = a.name
+		}
+	}
+	return km, nil
+}
+
+// parseKeySequence splits a binding such as "gg", "ctrl+w l" or "space" into
+// its keys. Space-separated words are separate keys; a word that isn't a
+// named key or modifier combination is read one character per key.
+func parseKeySequence(s string) []string {
+	if s == " " {
+		return []string{"space"}
+	}
+	var seq []string
+	for _, word := range strings.Fields(s) {
+		if len([]rune(word)) == 1 || namedKeys[word] || strings.Contains(word[1:], "+") {
+			seq = append(seq, word)
+			continue
+		}
+		for _, r := range word {
+			seq = append(seq, string(r))
+		}
+	}
+	return seq
+}
+
+func keyName(msg tea.KeyMsg) string {
+	if key := msg.String(); key != " " {
+		return key
+	}
+	return "space"
+}
+
+// resolve looks seq up among the actions of scope. It returns the action
+// bound to exactly seq, if any, and whether a longer binding starts with it.
+func (km Keymap) resolve(scope int, seq []string) (action string, longer bool) {
+	for _, a := range keyActions {
+		if a.scope != scope {
+			continue
+		}
+		for _, bound := range km[a.name] {
+			switch {
+			case slices.Equal(bound, seq):
+				action = a.name
+			case len(bound) > len(seq) && slices.Equal(bound[:len(seq)], seq):
+				longer = true
+			}
+		}
+	}
+	return action, longer
+}
+
+// is reports whether the single key msg triggers action.
+func (km Keymap) is(action string, msg tea.KeyMsg) bool {
+	key := keyName(msg)
+	for _, seq := range km[action] {
+		if len(seq) == 1 && seq[0] == key {
+			return true
+		}
+	}
+	return false
+}
+
+var keySymbols = map[string]string{
+	"up": "↑", "down": "↓", "left": "←", "right": "→",
+	"shift+left": "⇧←", "shift+right": "⇧→",
+	"space": "Space", "enter": "Enter", "esc": "Esc", "tab": "Tab",
+}
+
+func formatKeySequence(seq []string) string {
+	parts := make([]string, len(seq))
+	joined := true
+	for i, k := range seq {
+		if sym, ok := keySymbols[k]; ok {
+			k = sym
+		}
+		if len([]rune(k)) > 1 {
+			joined = false
+		}
+		parts[i] = k
+	}
+	if joined {
+		return strings.Join(parts, "")
+	}
+	return strings.Join(parts, " ")
+}
+
+// label is the primary key of action, as shown in the footer.
+func (km Keymap) label(action string) string {
+	if seqs := km[action]; len(seqs) > 0 {
+		return formatKeySequence(seqs[0])
+	}
+	return "—"
+}
+
+// labels lists every key of action, as shown in the help overlay.
+func (km Keymap) labels(action string) string {
+	var parts []string
+	for _, seq := range km[action] {
+		parts = append(parts, formatKeySequence(seq))
+	}
+	if len(parts) == 0 {
+		return "—"
+	}
+	return strings.Join(parts, "/")
+}
+
+// hint renders a footer entry such as "h/l column": the primary keys of
+// actions followed by text.
+func (km Keymap) hint(text string, actions ...string) string {
+	keys := make([]string, len(actions))
+	for i, a := range actions {
+		keys[i] = km.label(a)
+	}
+	return strings.Join(keys, "/") + " " + text
+}
+
+func (m Model) keymap() Keymap {
+	if m.keys != nil {
+		return m.keys
+	}
+	return defaultKeymap
+}
+
+// keyTimeoutMsg fires a pending key sequence that is a complete binding
+// once no longer sequence has been typed in time.
+type keyTimeoutMsg struct {
+	id int
+}
+
+// resolveNormalKey feeds msg into the pending key sequence. It returns the
+// actions to run: usually one, none while a sequence is still being typed,
+// and two when an ambiguous prefix is followed by an unrelated key.
+func (m *Model) resolveNormalKey(msg tea.KeyMsg) ([]string, tea.Cmd) {
+	km := m.keymap()
+	key := keyName(msg)
+	seq := append(slices.Clone(m.pendingKeys), key)
+
+	action, longer := km.resolve(scopeNormal, seq)
+	if longer {
+		m.pendingKeys = seq
+		m.pendingID++
+		id := m.pendingID
+		return nil, tea.Tick(keySequenceTimeout, func(time.Time) tea.Msg { return keyTimeoutMsg{id: id} })
+	}
+
+	pending := m.pendingKeys
+	m.pendingKeys = nil
+	if action != "" {
+		return []string{action}, nil
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	// The pending keys didn't grow into a longer binding: fire them if they
+	// were a binding of their own, then read key afresh.
+	var actions []string
+	if prev, _ := km.resolve(scopeNormal, pending); prev != "" {
+		actions = append(actions, prev)
+	}
+	rest, cmd := m.resolveNormalKey(msg)
+	return append(actions, rest...), cmd
+}
+
+type helpEntry struct {
+	actions []string
+	keys    string
+	text    string
+}
+
+// helpSections lays out the help overlay. Keys are filled in from the active
+// bindings unless the entry names them itself.
+var helpSections = []struct {
+	title   string
+	entries []helpEntry
+}{
+	{"Navigation", []helpEntry{
+		{actions: []string{"down", "up"}, text: "Move down/up"},
+		{actions: []string{"left", "right"}, text: "Sidebar / Content"},
+		{actions: []string{"toggle_focus"}, text: "Toggle focus"},
+		{actions: []string{"top", "bottom"}, text: "First / last task"},
+		{actions: []string{"view_today", "view_upcoming", "view_logbook", "view_calendar", "view_week", "view_board", "view_inbox"}, text: "Today / Upcoming / Logbook /\nCalendar / Week / Board / Inbox"},
+		{actions: []string{"prev_day", "next_day"}, text: "Logbook: prev/next day"},
+		{actions: []string{"open"}, text: "Toggle done"},
+	}},
+	{"Actions", []helpEntry{
+		{actions: []string{"new"}, text: "New task"},
+		{actions: []string{"edit"}, text: "Edit task"},
+		{actions: []string{"edit_form"}, text: "Edit all fields"},
+		{actions: []string{"open_editor"}, text: "Open note in $EDITOR at the task"},
+		{actions: []string{"open_obsidian"}, text: "Open note in Obsidian"},
+		{actions: []string{"done"}, text: "Toggle done/reopen"},
+		{actions: []string{"follow_up"}, text: "Create follow-up for tomorrow"},
+		{actions: []string{"reschedule"}, text: "Reschedule task"},
+		{actions: []string{"triage"}, text: "Triage the inbox"},
+		{actions: []string{"priority"}, text: "Set priority"},
+		{actions: []string{"separators"}, text: "Toggle priority separators"},
+		{actions: []string{"wrap"}, text: "Toggle soft wrap"},
+		{actions: []string{"details"}, text: "Toggle task detail pane"},
+		{actions: []string{"cancel"}, text: "Cancel task"},
+		{actions: []string{"tag_add", "tag_remove"}, text: "Add / remove tags"},
+		{actions: []string{"filter"}, text: "Filter by text"},
+		{actions: []string{"reload"}, text: "Reload from files"},
+		{actions: []string{"palette"}, text: "Command palette"},
+	}},
+	{"Calendar", []helpEntry{
+		{actions: []string{"left", "down", "up", "right"}, text: "Move between days"},
+		{actions: []string{"prev_month", "next_month"}, text: "Previous / next month"},
+		{actions: []string{"open"}, text: "Show the day's tasks"},
+		{actions: []string{"reschedule"}, text: "Pick a day to reschedule to"},
+		{actions: []string{"clear"}, text: "Back to the month grid"},
+	}},
+	{"Week", []helpEntry{
+		{actions: []string{"left", "right"}, text: "Move between day columns"},
+		{actions: []string{"move_left", "move_right"}, text: "Move task to previous/next day"},
+	}},
+	{"Board", []helpEntry{
+		{actions: []string{"left", "right"}, text: "Move between status columns"},
+		{actions: []string{"move_left", "move_right"}, text: "Move task to previous/next status"},
+	}},
+	{"Sync", []helpEntry{
+		{keys: "Auto-sync", text: "Reloads when daily note files change"},
+		{actions: []string{"reload"}, text: "Manual fallback reload"},
+	}},
+	{"Bulk Selection", []helpEntry{
+		{actions: []string{"select"}, text: "Toggle select"},
+		{actions: []string{"select_all"}, text: "Select/deselect all"},
+		{actions: []string{"visual"}, text: "Visual mode: j/k extend a range"},
+		{actions: []string{"done"}, text: "Mark selected done"},
+		{actions: []string{"reschedule"}, text: "Reschedule selected"},
+		{actions: []string{"priority"}, text: "Set priority of selected"},
+		{actions: []string{"follow_up"}, text: "Follow up on selected"},
+		{actions: []string{"cancel"}, text: "Cancel selected"},
+		{actions: []string{"tag_add", "tag_remove"}, text: "Tag / untag selected"},
+		{actions: []string{"clear"}, text: "Clear selection"},
+	}},
+}
+
+// helpText renders helpSections with the keys of km.
+func (km Keymap) helpText() string {
+	const keyWidth = 16
+	var b strings.Builder
+	b.WriteString("\n  Obsidian Tasks TUI\n")
+	for _, section := range helpSections {
+		b.WriteString("\n  " + section.title + "\n")
+		for _, e := range section.entries {
+			keys := e.keys
+			if keys == "" && len(e.actions) == 1 {
+				keys = km.labels(e.actions[0])
+			} else if keys == "" {
+				labels := make([]string, len(e.actions))
+				for i, a := range e.actions {
+					labels[i] = km.label(a)
+				}
+				keys = strings.Join(labels, "/")
+			}
+			pad := max(1, keyWidth-len([]rune(keys)))
+			lines := strings.Split(e.text, "\n")
+			b.WriteString("    " + keys + strings.Repeat(" ", pad) + lines[0] + "\n")
+			for _, line := range lines[1:] {
+				b.WriteString("    " + strings.Repeat(" ", len([]rune(keys))+pad) + line + "\n")
+			}
+		}
+	}
+	b.WriteString("\n  Press any key to close.\n")
+	return b.String()
+}
@@ -4,6 +4,9 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -14,16 +17,19 @@ func main() {
 
 	flag.StringVar(&vaultPath, "vault", "", "Path to Obsidian vault")
 	flag.StringVar(&configPath, "config", "", "Path to config file")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags]\n       %s [flags] add <task text>\n\nFlags:\n", os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
-	cfg, err := LoadConfig(configPath)
+	cfg, err := LoadConfig(configPath, vaultPath)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 		os.Exit(1)
 	}
-
-	if vaultPath != "" {
-		cfg.Vault.Path = vaultPath
+	for _, w := range cfg.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
 	}
 
 	if cfg.Vault.Path == "" {
@@ -31,6 +37,18 @@ func main() {
 		os.Exit(1)
 	}
 
+	if args := flag.Args(); len(args) > 0 {
+		if args[0] != "add" || len(args) < 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		if err := runAdd(cfg, strings.Join(args[1:], " ")); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	tasks, err := ScanDailyNotes(cfg)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error scanning tasks: %v\n", err)
@@ -38,10 +56,33 @@ func main() {
 	}
 
 	model := NewModel(cfg, tasks)
-	p := tea.NewProgram(model, tea.WithAltScreen())
+	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
 
 	if _, err := p.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// runAdd creates a task from quick-add text without starting the TUI, e.g.
+// `obsidian-tasks-tui add "Call the bank due next friday #finance"`.
+func runAdd(cfg Config, text string) error {
+	parser := newDateParser(cfg)
+	defaultDue := parser.Today
+	if cfg.Vault.InboxFile != "" {
+		defaultDue = time.Time{}
+	}
+	task := parseQuickAdd(text, defaultDue, parser.Parse)
+	if task.Description == "" {
+		return fmt.Errorf("task description is empty")
+	}
+	if err := CreateTask(cfg, task); err != nil {
+		return err
+	}
+	notePath := filepath.Join(cfg.Vault.DailyNotesDir, dailyNoteName(cfg, task.DueDate))
+	if task.IsUndated() {
+		notePath = cfg.Vault.InboxFile
+	}
+	fmt.Printf("%s → %s\n", formatTaskLine(task), notePath)
+	return nil
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// listItemRe matches a bullet or numbered list item, inside blockquotes too.
+var listItemRe = regexp.MustCompile(`^\s*(?:>\s?)*\s*(?:[-*+]|\d+[.)])\s`)
+
+// lineClassifier walks a note line by line and reports which lines are
+// content Obsidian would render as text. Lines in YAML frontmatter, fenced or
+// indented code blocks, %% comments %% and <!-- HTML comments --> are not.
+type lineClassifier struct {
+	lines           int
+	frontmatter     bool
+	fence           string
+	indentedCode    bool
+	obsidianComment bool
+	htmlComment     bool
+	previousBlank   bool
+	inList          bool
+}
+
+// isContent classifies the next line of the note.
+func (c *lineClassifier) isContent(line string) bool {
+	c.lines++
+	trimmed := strings.TrimSpace(line)
+	blank := trimmed == ""
+	defer func() { c.previousBlank = blank }()
+
+	if c.lines == 1 && trimmed == "---" {
+		c.frontmatter = true
+		return false
+	}
+	if c.frontmatter {
+		if trimmed == "---" || trimmed == "..." {
+			c.frontmatter = false
+		}
+		return false
+	}
+
+	// Fences may sit inside blockquotes and callouts.
+	unquoted := strings.TrimSpace(strings.TrimLeft(trimmed, "> "))
+	if c.fence != "" {
+		if strings.HasPrefix(unquoted, c.fence) && strings.Trim(unquoted, c.fence[:1]) == "" {
+			c.fence = ""
+		}
+		return false
+	}
+
+	if c.indentedCode {
+		if blank || indentWidth(line) >= 4 {
+			return false
+		}
+		c.indentedCode = false
+	}
+
+	if c.obsidianComment || c.htmlComment {
+		c.scanComments(line)
+		return false
+	}
+	if strings.HasPrefix(trimmed, "%%") || strings.HasPrefix(trimmed, "<!--") {
+		c.scanComments(line)
+		return false
+	}
+
+	if !c.inList && (c.previousBlank || c.lines == 1) && indentWidth(line) >= 4 && !blank {
+		c.indentedCode = true
+		return false
+	}
+	if fence := openingFence(unquoted); fence != "" {
+		c.fence = fence
+		return false
+	}
+
+	switch {
+	case listItemRe.MatchString(line):
+		c.inList = true
+	case !blank && indentWidth(line) == 0:
+		c.inList = false
+	}
+	c.scanComments(line)
+	return true
+}
+
+// scanComments tracks comments opened and closed on line, so that one left
+// open hides the lines that follow it.
+func (c *lineClassifier) scanComments(line string) {
+	for line != "" {
+		switch {
+		case c.htmlComment:
+			end := strings.Index(line, "-->")
+			if end < 0 {
+				return
+			}
+			c.htmlComment = false
+			line = line[end+3:]
+		case c.obsidianComment:
+			end := strings.Index(line, "%%")
+			if end < 0 {
+				return
+			}
+			c.obsidianComment = false
+			line = line[end+2:]
+		default:
+			html, obsidian := strings.Index(line, "<!--"), strings.Index(line, "%%")
+			switch {
+			case html >= 0 && (obsidian < 0 || html < obsidian):
+				c.htmlComment = true
+				line = line[html+4:]
+			case obsidian >= 0:
+				c.obsidianComment = true
+				line = line[obsidian+2:]
+			default:
+				return
+			}
+		}
+	}
+}
+
+// openingFence returns the ``` or ~~~ run that opens a fenced code block on
+// line, or "".
+func openingFence(line string) string {
+	for _, ch := range []string{"`", "~"} {
+		n := len(line) - len(strings.TrimLeft(line, ch))
+		if n >= 3 {
+			return strings.Repeat(ch, n)
+		}
+	}
+	return ""
+}
+
+// indentWidth measures leading whitespace, counting a tab as four spaces.
+func indentWidth(line string) int {
+	width := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += 4
+		default:
+			return width
+		}
+	}
+	return width
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// doubleClickInterval is how soon a second click on the same task has to
+// follow the first to count as a double-click.
+const doubleClickInterval = 400 * time.Millisecond
+
+// listLayout returns the rows of the list views along with the task shown on
+// each row; ok is false for the calendar, week and board views, which
+// boardCardAt and calendarAt map instead.
+func (m Model) listLayout(maxWidth int) (rows []string, selectedLine int, lineTasks []int, ok bool) {
+	switch m.activeView {
+	case viewToday:
+		rows, selectedLine, lineTasks = m.todayLayout(maxWidth)
+	case viewUpcoming:
+		rows, selectedLine, lineTasks = m.upcomingLayout(maxWidth)
+	case viewLogbook:
+		rows, selectedLine, lineTasks = m.logbookLayout(maxWidth)
+	case viewInbox:
+		rows, selectedLine, lineTasks = m.inboxLayout(maxWidth)
+	default:
+		return nil, -1, nil, false
+	}
+	return rows, selectedLine, lineTasks, true
+}
+
+// taskAt maps a click inside the content pane, relative to its top-left
+// border corner, to a position in currentViewTasks, or -1.
+func (m Model) taskAt(x, y, contentWidth, contentHeight int) int {
+	rows, selectedLine, lineTasks, ok := m.listLayout(contentWidth - 4)
+	viewportHeight := max(0, contentHeight-3)
+	line := y - 1
+	if !ok || x < 1 || x > contentWidth || line < 0 || line >= viewportHeight {
+		return -1
+	}
+	lines, first, span, owner := flattenRows(rows, selectedLine)
+	line += m.scrollOffsetFor(len(lines), first, span, viewportHeight)
+	if line >= len(owner) || owner[line] >= len(lineTasks) || lineTasks[owner[line]] < 0 {
+		return -1
+	}
+	return slices.Index(m.currentViewTasks(), lineTasks[owner[line]])
+}
+
+// boardCardAt maps a click in the week or board view to a column and a
+// position in it. pos is -1 above the cards or on the "+N more" line, and
+// bullet reports a click on the card's bullet.
+func (m Model) boardCardAt(x, y, contentWidth, contentHeight int) (col, pos int, bullet bool) {
+	var cols []boardColumn
+	if m.activeView == viewWeek {
+		cols = m.weekColumns()
+	} else {
+		cols = m.statusColumns()
+	}
+	viewportHeight := max(0, contentHeight-3)
+	// The board sits below the title and a blank line, indented by two.
+	cx, line := x-3, y-1-2
+	if len(cols) == 0 || cx < 0 || x > contentWidth || line < 0 || line >= viewportHeight-2 {
+		return -1, -1, false
+	}
+	colWidth, cardsHeight := boardLayout(len(cols), contentWidth-4, viewportHeight-2)
+	col, cardX := cx/(colWidth+1), cx%(colWidth+1)
+	if col >= len(cols) || cardX == colWidth {
+		return -1, -1, false
+	}
+
+	// Cards start below the column title, its badge and the rule.
+	card := line - 3
+	if card < 0 {
+		return col, -1, false
+	}
+	tasks := cols[col].Tasks
+	offset := m.boardOffset(col, cardsHeight)
+	end := min(len(tasks), offset+cardsHeight)
+	pos = offset + card
+	if pos >= end || pos == end-1 && end < len(tasks) {
+		return col, -1, false
+	}
+	return col, pos, cardX < 2
+}
+
+// calendarAt maps a click in the calendar view to a day in the month grid or
+// to a position in the day's task list. day is zero and pos is -1 when the
+// click hits neither.
+func (m Model) calendarAt(x, y, contentWidth, contentHeight int) (day time.Time, pos int, bullet bool) {
+	maxWidth := contentWidth - 4
+	viewportHeight := max(0, contentHeight-3)
+	line := y - 1
+	if x < 1 || x > contentWidth || line < 0 || line >= viewportHeight {
+		return time.Time{}, -1, false
+	}
+	rows, selectedLine := m.renderCalendarRows(maxWidth)
+	lines, first, span, owner := flattenRows(rows, selectedLine)
+	line += m.scrollOffsetFor(len(lines), first, span, viewportHeight)
+	if line >= len(owner) {
+		return time.Time{}, -1, false
+	}
+
+	// The grid follows the title, a blank line and the weekday header; the
+	// task list follows the grid, a blank line and the day's heading.
+	row, cx := owner[line], x-1
+	start, weeks := m.calendarGrid()
+	switch {
+	case row >= 3 && row < 3+weeks:
+		cellWidth := calendarCellWidth(maxWidth)
+		if cx < 2 || (cx-2)/cellWidth >= 7 {
+			return time.Time{}, -1, false
+		}
+		return start.AddDate(0, 0, 7*(row-3)+(cx-2)/cellWidth), -1, false
+	case row >= 3+weeks+2 && row-(3+weeks+2) < len(m.currentViewTasks()):
+		return time.Time{}, row - (3 + weeks + 2), cx < 4
+	}
+	return time.Time{}, -1, false
+}
+
+func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.mode != modeNormal || m.width == 0 {
+		return m, nil
+	}
+	_, sidebarWidth, contentWidth, _, contentHeight := m.paneLayout()
+	x, y := msg.X-hPad, msg.Y
+	contentLeft := sidebarWidth + 2
+	inSidebar := x < contentLeft
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp, tea.MouseButtonWheelDown:
+		if msg.Action != tea.MouseActionPress {
+			return m, nil
+		}
+		if inSidebar {
+			m.focus = focusSidebar
+		} else {
+			m.focus = focusContent
+		}
+		if msg.Button == tea.MouseButtonWheelUp {
+			return m.runAction("up")
+		}
+		return m.runAction("down")
+
+	case tea.MouseButtonLeft:
+		if msg.Action != tea.MouseActionPress {
+			return m, nil
+		}
+	default:
+		return m, nil
+	}
+
+	m.pendingKeys = nil
+	if inSidebar {
+		if item := y - 2; item >= 0 && item < len(sidebarItems) {
+			m.setActiveView(sidebarItems[item].view)
+			m.focus = focusSidebar
+		}
+		return m, nil
+	}
+
+	m.focus = focusContent
+	var pos int
+	var bullet bool
+	switch m.activeView {
+	case viewWeek, viewBoard:
+		var col int
+		col, pos, bullet = m.boardCardAt(x-contentLeft, y, contentWidth, contentHeight)
+		if col < 0 {
+			return m, nil
+		}
+		if col != m.boardCol {
+			m.boardCol, m.contentCursor = col, 0
+		}
+	case viewCalendar:
+		var day time.Time
+		day, pos, bullet = m.calendarAt(x-contentLeft, y, contentWidth, contentHeight)
+		if !day.IsZero() {
+			m.calendarCursor = day
+			if m.calendarPickFrom.IsZero() {
+				m.calendarTasks = false
+				m.contentCursor = 0
+				m.scrollOffset = 0
+			}
+			return m, nil
+		}
+		if pos >= 0 && !m.calendarPickFrom.IsZero() {
+			return m, nil
+		}
+		if pos >= 0 {
+			m.calendarTasks = true
+		}
+	default:
+		pos = m.taskAt(x-contentLeft, y, contentWidth, contentHeight)
+		// The bullet sits in the first few columns of every task row.
+		bullet = x-contentLeft-1 < 4
+	}
+	if pos < 0 {
+		return m, nil
+	}
+	m.contentCursor = pos
+
+	idx := m.currentViewTasks()[pos]
+	now := time.Now()
+	double := idx == m.lastClickTask && now.Sub(m.lastClickAt) < doubleClickInterval
+	m.lastClickTask, m.lastClickAt = idx, now
+
+	if bullet {
+		m.lastClickAt = time.Time{}
+		return m.runAction("open")
+	}
+	if double {
+		m.lastClickAt = time.Time{}
+		return m.runAction("edit")
+	}
+	return m, nil
+}
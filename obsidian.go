@@ -0,0 +1,256 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// periodicNoteSettings is how Obsidian's daily notes core plugin and the
+// Periodic Notes community plugin store a note kind's location.
+type periodicNoteSettings struct {
+	Enabled  *bool  `json:"enabled"`
+	Folder   string `json:"folder"`
+	Format   string `json:"format"`
+	Template string `json:"template"`
+}
+
+// periodicNotesPlugin is the Periodic Notes plugin's data.json.
+type periodicNotesPlugin struct {
+	Daily   *periodicNoteSettings `json:"daily"`
+	Weekly  *periodicNoteSettings `json:"weekly"`
+	Monthly *periodicNoteSettings `json:"monthly"`
+}
+
+func periodicNotesPath(vaultPath string) string {
+	return filepath.Join(vaultPath, ".obsidian", "plugins", "periodic-notes", "data.json")
+}
+
+// readDailyNoteSettings returns the vault's daily note settings, preferring
+// the Periodic Notes plugin when it has daily notes enabled, and the file
+// they came from. ok is false when the vault has neither.
+func readDailyNoteSettings(vaultPath string) (settings periodicNoteSettings, source string, ok bool) {
+	periodic := periodicNotesPath(vaultPath)
+	var plugin periodicNotesPlugin
+	if readJSON(periodic, &plugin) && plugin.Daily != nil && (plugin.Daily.Enabled == nil || *plugin.Daily.Enabled) {
+		return *plugin.Daily, periodic, true
+	}
+
+	core := filepath.Join(vaultPath, ".obsidian", "daily-notes.json")
+	if readJSON(core, &settings) {
+		return settings, core, true
+	}
+	return settings, "", false
+}
+
+func readJSON(path string, v any) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
+// applyDailyNoteSettings fills in the daily notes folder and format from the
+// vault's Obsidian settings, unless config.toml sets them, and the template
+// when template_path is empty. isSet reports whether a [vault] key was set. A
+// format with no Go equivalent is skipped with a warning.
+func applyDailyNoteSettings(cfg *Config, isSet func(key string) bool) {
+	dirSet, formatSet := isSet("daily_notes_dir"), isSet("daily_note_format")
+	if cfg.Vault.Path == "" || (dirSet && formatSet && cfg.Vault.TemplatePath != "") {
+		return
+	}
+	settings, source, ok := readDailyNoteSettings(cfg.Vault.Path)
+	if !ok {
+		return
+	}
+	if !dirSet {
+		cfg.Vault.DailyNotesDir = strings.Trim(filepath.FromSlash(settings.Folder), string(filepath.Separator))
+	}
+	if cfg.Vault.TemplatePath == "" {
+		cfg.Vault.TemplatePath = strings.TrimSpace(settings.Template)
+	}
+	if !formatSet {
+		format := settings.Format
+		if format == "" {
+			format = "YYYY-MM-DD"
+		}
+		layout, err := momentToGoLayout(format)
+		if err != nil {
+			cfg.Warnings = append(cfg.Warnings, fmt.Sprintf("%s: daily note format %q: %v; using %q, set daily_note_format in config.toml", source, format, err, cfg.Vault.DailyNoteFormat))
+			return
+		}
+		cfg.Vault.DailyNoteFormat = layout
+	}
+}
+
+// applyPeriodicNoteSettings reads the weekly and monthly notes the Periodic
+// Notes plugin has enabled, unless config.toml sets their format. Their
+// moment formats are used as they are.
+func applyPeriodicNoteSettings(cfg *Config, isSet func(key string) bool) {
+	var plugin periodicNotesPlugin
+	if cfg.Vault.Path == "" || !readJSON(periodicNotesPath(cfg.Vault.Path), &plugin) {
+		return
+	}
+	apply := func(settings *periodicNoteSettings, name, defaultFormat string, dir, format *string) {
+		if settings == nil || settings.Enabled == nil || !*settings.Enabled || isSet(name+"_note_format") {
+			return
+		}
+		*format = settings.Format
+		if *format == "" {
+			*format = defaultFormat
+		}
+		if !isSet(name + "_notes_dir") {
+			*dir = strings.Trim(filepath.FromSlash(settings.Folder), string(filepath.Separator))
+		}
+	}
+	apply(plugin.Weekly, "weekly", "gggg-[W]ww", &cfg.Vault.WeeklyNotesDir, &cfg.Vault.WeeklyNoteFormat)
+	apply(plugin.Monthly, "monthly", "YYYY-MM", &cfg.Vault.MonthlyNotesDir, &cfg.Vault.MonthlyNoteFormat)
+}
+
+// momentTokens maps moment.js format tokens to Go layout elements.
+var momentTokens = map[string]string{
+	"YYYY": "2006", "YY": "06",
+	"MMMM": "January", "MMM": "Jan", "MM": "01", "M": "1",
+	"DD": "02", "D": "2",
+	"dddd": "Monday", "ddd": "Mon",
+	"HH": "15", "hh": "03", "h": "3",
+	"mm": "04", "m": "4",
+	"ss": "05", "s": "5",
+	"A": "PM", "a": "pm",
+}
+
+// goLayoutWords are the runs a Go layout would read as a date element, so
+// they can't appear in literal text.
+var goLayoutWords = []string{"Jan", "Mon", "MST", "PM", "pm", "Z07", "_2"}
+
+// momentToGoLayout translates a moment.js date format, as used by Obsidian,
+// into a Go time layout. Text in [brackets] is literal; tokens with no Go
+// equivalent, such as week numbers and ordinals, are an error.
+func momentToGoLayout(format string) (string, error) {
+	var b strings.Builder
+	err := scanMoment(format, func(token string) error {
+		layout, ok := momentTokens[token]
+		if !ok {
+			return fmt.Errorf("unsupported token %q", token)
+		}
+		b.WriteString(layout)
+		return nil
+	}, func(text string) error {
+		if strings.ContainsAny(text, "0123456789") {
+			return fmt.Errorf("literal %q would be read as a date", text)
+		}
+		for _, word := range goLayoutWords {
+			if strings.Contains(text, word) {
+				return fmt.Errorf("literal %q would be read as a date", text)
+			}
+		}
+		b.WriteString(text)
+		return nil
+	})
+	return b.String(), err
+}
+
+// formatMoment formats t with a moment.js format directly, which unlike a Go
+// layout can carry week numbers: ww and gggg count weeks from weekStart, WW
+// and GGGG are ISO weeks. A week starting on Monday is numbered the ISO way;
+// otherwise week 1 is the one holding January 1st, as moment's en locale does.
+func formatMoment(t time.Time, format string, weekStart time.Weekday) (string, error) {
+	var b strings.Builder
+	err := scanMoment(format, func(token string) error {
+		isoYear, isoWeek := t.ISOWeek()
+		year, week := localeWeek(t, weekStart)
+		switch token {
+		case "W", "WW", "w", "ww":
+			n := week
+			if token[0] == 'W' {
+				n = isoWeek
+			}
+			if len(token) == 2 {
+				fmt.Fprintf(&b, "%02d", n)
+			} else {
+				fmt.Fprintf(&b, "%d", n)
+			}
+		case "GGGG", "gggg":
+			n := year
+			if token[0] == 'G' {
+				n = isoYear
+			}
+			fmt.Fprintf(&b, "%04d", n)
+		case "GG", "gg":
+			n := year
+			if token[0] == 'G' {
+				n = isoYear
+			}
+			fmt.Fprintf(&b, "%02d", n%100)
+		case "Q":
+			fmt.Fprintf(&b, "%d", (int(t.Month())+2)/3)
+		default:
+			layout, ok := momentTokens[token]
+			if !ok {
+				return fmt.Errorf("unsupported token %q", token)
+			}
+			b.WriteString(t.Format(layout))
+		}
+		return nil
+	}, func(text string) error {
+		b.WriteString(text)
+		return nil
+	})
+	return b.String(), err
+}
+
+// localeWeek returns the week-numbering year and week of t for weeks that
+// start on weekStart.
+func localeWeek(t time.Time, weekStart time.Weekday) (year, week int) {
+	if weekStart == time.Monday {
+		return t.ISOWeek()
+	}
+	// A week belongs to the year its last day falls in, so the week holding
+	// January 1st is week 1.
+	last := t.AddDate(0, 0, 6-(int(t.Weekday())-int(weekStart)+7)%7)
+	return last.Year(), (last.YearDay()-1)/7 + 1
+}
+
+// scanMoment splits a moment.js format into tokens, runs of one letter, and
+// literal text, which is either in [brackets] or not a letter.
+func scanMoment(format string, token, literal func(string) error) error {
+	for rest := format; rest != ""; {
+		if rest[0] == '[' {
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return fmt.Errorf("unclosed [ in %q", format)
+			}
+			if err := literal(rest[1:end]); err != nil {
+				return err
+			}
+			rest = rest[end+1:]
+			continue
+		}
+
+		c := rest[0]
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' {
+			end := 1
+			for end < len(rest) && rest[end] == c {
+				end++
+			}
+			tok := rest[:end]
+			if tok == "D" && strings.HasPrefix(rest[end:], "o") {
+				tok, end = "Do", end+1
+			}
+			if err := token(tok); err != nil {
+				return err
+			}
+			rest = rest[end:]
+			continue
+		}
+		if err := literal(rest[:1]); err != nil {
+			return err
+		}
+		rest = rest[1:]
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfigReadsObsidianDailyNoteSettings(t *testing.T) {
+	vault := t.TempDir()
+	write := func(path, content string) {
+		t.Helper()
+		path = filepath.Join(vault, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	noConfig := filepath.Join(t.TempDir(), "missing.toml")
+
+	write(".obsidian/daily-notes.json", `{"folder": "Journal/Daily/", "format": "YYYY/MM/YYYY-MM-DD ddd"}`)
+	cfg, err := LoadConfig(noConfig, vault)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Vault.DailyNotesDir != filepath.Join("Journal", "Daily") || cfg.Vault.DailyNoteFormat != "2006/01/2006-01-02 Mon" {
+		t.Fatalf("expected core plugin settings, got %q %q", cfg.Vault.DailyNotesDir, cfg.Vault.DailyNoteFormat)
+	}
+
+	write(".obsidian/plugins/periodic-notes/data.json", `{"daily": {"enabled": true, "folder": "Periodic", "format": "", "template": "Templates/Daily"}, "weekly": {"enabled": true, "folder": "Weekly/"}, "monthly": {"enabled": false, "format": "YYYY-MM"}}`)
+	cfg, err = LoadConfig(noConfig, vault)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Vault.DailyNotesDir != "Periodic" || cfg.Vault.DailyNoteFormat != "2006-01-02" || cfg.Vault.TemplatePath != "Templates/Daily" {
+		t.Fatalf("expected periodic notes settings, got %q %q %q", cfg.Vault.DailyNotesDir, cfg.Vault.DailyNoteFormat, cfg.Vault.TemplatePath)
+	}
+	if cfg.Vault.WeeklyNotesDir != "Weekly" || cfg.Vault.WeeklyNoteFormat != "gggg-[W]ww" || cfg.Vault.MonthlyNoteFormat != "" {
+		t.Fatalf("expected only weekly notes enabled, got %q %q %q", cfg.Vault.WeeklyNotesDir, cfg.Vault.WeeklyNoteFormat, cfg.Vault.MonthlyNoteFormat)
+	}
+
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(configPath, []byte("[vault]\ndaily_note_format = \"02-01-2006\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = LoadConfig(configPath, vault)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Vault.DailyNotesDir != "Periodic" || cfg.Vault.DailyNoteFormat != "02-01-2006" {
+		t.Fatalf("expected config.toml to override the format only, got %q %q", cfg.Vault.DailyNotesDir, cfg.Vault.DailyNoteFormat)
+	}
+
+	if err := os.WriteFile(configPath, []byte("[vault]\ntemplate_path = \"Templates/Mine\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = LoadConfig(configPath, vault)
+	if err != nil || cfg.Vault.TemplatePath != "Templates/Mine" {
+		t.Fatalf("expected config.toml's template to win, got %q (err %v)", cfg.Vault.TemplatePath, err)
+	}
+
+	write(".obsidian/plugins/periodic-notes/data.json", `{"daily": {"enabled": true, "folder": "Daily", "format": "dddd, MMMM Do YYYY"}}`)
+	cfg, err = LoadConfig(noConfig, vault)
+	if err != nil {
+		t.Fatalf("expected an untranslatable format not to stop loading, got %v", err)
+	}
+	if cfg.Vault.DailyNotesDir != "Daily" || cfg.Vault.DailyNoteFormat != "2006-01-02" || len(cfg.Warnings) != 1 || !strings.Contains(cfg.Warnings[0], `"Do"`) {
+		t.Fatalf("expected the default format and a warning, got %q %q %q", cfg.Vault.DailyNotesDir, cfg.Vault.DailyNoteFormat, cfg.Warnings)
+	}
+}
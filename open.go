@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// externalDoneMsg reports that an editor or opener launched for a task has
+// exited, so the tasks can be reloaded.
+type externalDoneMsg struct {
+	what string
+	err  error
+}
+
+// editorFields returns the configured editor command, falling back to
+// $VISUAL, $EDITOR and finally vi.
+func editorFields(cfg Config) []string {
+	for _, editor := range []string{cfg.Open.Editor, os.Getenv("VISUAL"), os.Getenv("EDITOR")} {
+		if fields := strings.Fields(editor); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{"vi"}
+}
+
+// editorArgs appends path and line to an editor command using the
+// convention that editor understands for jumping to a line.
+func editorArgs(editor []string, path string, line int) []string {
+	args := append([]string{}, editor...)
+	name := strings.TrimSuffix(filepath.Base(editor[0]), ".exe")
+	switch name {
+	case "code", "code-insiders", "codium", "cursor", "windsurf":
+		return append(args, "--goto", fmt.Sprintf("%s:%d", path, line))
+	case "hx", "helix", "subl", "zed":
+		return append(args, fmt.Sprintf("%s:%d", path, line))
+	default:
+		// vi, vim, nvim, nano, emacs, micro, kak and most others.
+		return append(args, fmt.Sprintf("+%d", line), path)
+	}
+}
+
+// obsidianURI builds an obsidian:// link to the task's note. With
+// advanced-uri enabled it also carries the line, which needs the Advanced
+// URI plugin.
+func obsidianURI(cfg Config, task Task) string {
+	vault := cfg.Open.VaultName
+	if vault == "" {
+		vault = filepath.Base(cfg.Vault.Path)
+	}
+	file := task.FilePath
+	if rel, err := filepath.Rel(cfg.Vault.Path, task.FilePath); err == nil {
+		file = rel
+	}
+	file = filepath.ToSlash(file)
+
+	if cfg.Open.AdvancedURI {
+		return fmt.Sprintf("obsidian://advanced-uri?vault=%s&filepath=%s&line=%d",
+			uriEscape(vault), uriEscape(file), task.LineNumber)
+	}
+	return fmt.Sprintf("obsidian://open?vault=%s&file=%s",
+		uriEscape(vault), uriEscape(strings.TrimSuffix(file, ".md")))
+}
+
+// uriEscape escapes a query value the way Obsidian expects, with %20 for
+// spaces rather than +.
+func uriEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func defaultOpener() []string {
+	switch runtime.GOOS {
+	case "darwin":
+		return []string{"open"}
+	case "windows":
+		return []string{"rundll32", "url.dll,FileProtocolHandler"}
+	default:
+		return []string{"xdg-open"}
+	}
+}
+
+// openInEditor suspends the UI and opens the task's note at its line.
+func (m Model) openInEditor() (tea.Model, tea.Cmd) {
+	task := m.selectedTask()
+	if task == nil {
+		return m, nil
+	}
+	args := editorArgs(editorFields(m.cfg), task.FilePath, task.LineNumber)
+	cmd := exec.Command(args[0], args[1:]...)
+	return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return externalDoneMsg{what: args[0], err: err}
+	})
+}
+
+// openInObsidian hands the task's obsidian:// URI to the opener command.
+func (m Model) openInObsidian() (tea.Model, tea.Cmd) {
+	task := m.selectedTask()
+	if task == nil {
+		return m, nil
+	}
+	args := strings.Fields(m.cfg.Open.Opener)
+	if len(args) == 0 {
+		args = defaultOpener()
+	}
+	args = append(args, obsidianURI(m.cfg, *task))
+	m.statusMsg = "Opening in Obsidian…"
+	m.statusTime = time.Now()
+	return m, func() tea.Msg {
+		err := exec.Command(args[0], args[1:]...).Run()
+		return externalDoneMsg{what: args[0], err: err}
+	}
+}
+
+func (m Model) handleExternalDone(msg externalDoneMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.err = msg.err
+		m.statusMsg = "Error: " + msg.what + ": " + msg.err.Error()
+		m.statusTime = time.Now()
+		return m, nil
+	}
+	return m.reload(), nil
+}
@@ -0,0 +1,337 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// paletteLimit is the number of matches the palette lists at once.
+const paletteLimit = 10
+
+// paletteCommand is one entry of the command palette. Commands with an arg
+// placeholder read the text typed after their name.
+type paletteCommand struct {
+	name   string
+	arg    string
+	desc   string
+	action string
+	run    func(m Model, arg string) (tea.Model, tea.Cmd)
+}
+
+// paletteCommands lists the commands that take arguments followed by every
+// normal-mode action.
+func paletteCommands() []paletteCommand {
+	cmds := []paletteCommand{
+		{name: "reschedule", arg: "<date>", desc: "Reschedule the selection or task", action: "reschedule", run: paletteReschedule},
+		{name: "priority", arg: "<level>", desc: "Set priority: highest … lowest, 1-5, none", action: "priority", run: palettePriority},
+		{name: "filter", arg: "<text>", desc: "Filter by text", action: "filter", run: paletteFilter},
+		{name: "new", arg: "<task>", desc: "Create a task with quick-add syntax", action: "new", run: paletteNew},
+		{name: "tag", arg: "<+#add -#remove>", desc: "Add or remove tags on the selection or task", action: "tag_add", run: paletteTag},
+		{name: "rename_tag", arg: "<#old #new>", desc: "Rename a tag across the whole vault", run: paletteRenameTag},
+		{name: "theme", arg: "<name>", desc: "Switch theme: " + strings.Join(themeNames(), ", "), run: paletteTheme},
+		{name: "export", arg: "[file]", desc: "Write the current view's tasks to a markdown file", run: paletteExport},
+	}
+	taken := make(map[string]bool)
+	for _, c := range cmds {
+		taken[c.name] = true
+	}
+	for _, a := range keyActions {
+		if a.scope != scopeNormal || a.name == "palette" || taken[a.name] {
+			continue
+		}
+		action := a.name
+		cmds = append(cmds, paletteCommand{
+			name:   a.name,
+			desc:   a.desc,
+			action: a.name,
+			run:    func(m Model, _ string) (tea.Model, tea.Cmd) { return m.runAction(action) },
+		})
+	}
+	return cmds
+}
+
+func themeNames() []string {
+	names := make([]string, 0, len(themePresets))
+	for name := range themePresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fuzzyScore reports whether every rune of pattern appears in s in order,
+// scoring consecutive runs, word starts and prefixes higher.
+func fuzzyScore(pattern, s string) (int, bool) {
+	pattern, s = strings.ToLower(pattern), strings.ToLower(s)
+	if pattern == "" {
+		return 0, true
+	}
+	if strings.HasPrefix(s, pattern) {
+		return 100 + len(pattern)*10 - len(s), true
+	}
+
+	target := []rune(s)
+	score, pos, prev := 0, 0, -2
+	for _, r := range pattern {
+		found := -1
+		for i := pos; i < len(target); i++ {
+			if target[i] == r {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			return 0, false
+		}
+		score++
+		if found == prev+1 {
+			score += 5
+		}
+		if found == 0 || strings.ContainsRune(" _-/", target[found-1]) {
+			score += 3
+		}
+		prev, pos = found, found+1
+	}
+	return score - len(target)/4, true
+}
+
+// splitPaletteInput separates the command name from its argument.
+func splitPaletteInput(value string) (string, string) {
+	value = strings.TrimLeft(value, " :")
+	name, arg, _ := strings.Cut(value, " ")
+	return name, strings.TrimSpace(arg)
+}
+
+// paletteMatches ranks the commands against the name typed so far. Names
+// match first; descriptions catch the rest.
+func (m Model) paletteMatches() []paletteCommand {
+	query, _ := splitPaletteInput(m.input.Value())
+	type scored struct {
+		cmd   paletteCommand
+		score int
+	}
+	var matches []scored
+	for _, c := range paletteCommands() {
+		if score, ok := fuzzyScore(query, c.name); ok {
+			matches = append(matches, scored{c, score + 1000})
+		} else if score, ok := fuzzyScore(query, c.desc); ok && len(query) > 1 {
+			matches = append(matches, scored{c, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	cmds := make([]paletteCommand, len(matches))
+	for i, s := range matches {
+		cmds[i] = s.cmd
+	}
+	return cmds
+}
+
+func (m Model) openPalette() (tea.Model, tea.Cmd) {
+	m.mode = modePalette
+	m.paletteCursor = 0
+	m.input.Placeholder = "command, e.g. reschedule fri"
+	m.input.SetValue("")
+	m.input.Focus()
+	return m, m.input.Cursor.BlinkCmd()
+}
+
+func (m Model) handlePalette(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	matches := m.paletteMatches()
+	switch msg.String() {
+	case "esc":
+		m.mode = modeNormal
+		m.input.Blur()
+		return m, nil
+	case "up", "ctrl+k", "ctrl+p":
+		if m.paletteCursor > 0 {
+			m.paletteCursor--
+		}
+		return m, nil
+	case "down", "ctrl+j", "ctrl+n":
+		if m.paletteCursor < len(matches)-1 {
+			m.paletteCursor++
+		}
+		return m, nil
+	case "tab":
+		if m.paletteCursor < len(matches) {
+			_, arg := splitPaletteInput(m.input.Value())
+			cmd := matches[m.paletteCursor]
+			value := cmd.name
+			if cmd.arg != "" {
+				value += " " + arg
+			}
+			m.input.SetValue(value)
+			m.input.CursorEnd()
+		}
+		return m, nil
+	case "enter":
+		_, arg := splitPaletteInput(m.input.Value())
+		m.mode = modeNormal
+		m.input.SetValue("")
+		m.input.Blur()
+		if m.paletteCursor >= len(matches) {
+			m.statusMsg = "No matching command"
+			m.statusTime = time.Now()
+			return m, nil
+		}
+		cmd := matches[m.paletteCursor]
+		if arg != "" && cmd.arg == "" {
+			m.statusMsg = cmd.name + " takes no argument"
+			m.statusTime = time.Now()
+			return m, nil
+		}
+		return cmd.run(m, arg)
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	m.paletteCursor = 0
+	return m, cmd
+}
+
+func paletteReschedule(m Model, arg string) (tea.Model, tea.Cmd) {
+	if arg == "" {
+		return m.runAction("reschedule")
+	}
+	newDate, err := m.parseDate(arg)
+	if err != nil {
+		m.statusMsg = "Invalid date: " + arg
+		m.statusTime = time.Now()
+		return m, nil
+	}
+	return m.rescheduleSelection(newDate), nil
+}
+
+func palettePriority(m Model, arg string) (tea.Model, tea.Cmd) {
+	if arg == "" {
+		return m.runAction("priority")
+	}
+	priority, err := parsePriority(arg)
+	if err != nil {
+		m.statusMsg = "Error: " + err.Error()
+		m.statusTime = time.Now()
+		return m, nil
+	}
+	return m.setTargetsPriority(priority), nil
+}
+
+func paletteFilter(m Model, arg string) (tea.Model, tea.Cmd) {
+	if arg == "" {
+		return m.runAction("filter")
+	}
+	m.filter = arg
+	m.buildViews()
+	return m, nil
+}
+
+func paletteNew(m Model, arg string) (tea.Model, tea.Cmd) {
+	if arg == "" {
+		return m.runAction("new")
+	}
+	return m.createQuickAddTask(arg), nil
+}
+
+func paletteTag(m Model, arg string) (tea.Model, tea.Cmd) {
+	if arg == "" {
+		return m.runAction("tag_add")
+	}
+	add, remove := parseTagOps(arg)
+	return m.editTargetTags(add, remove), nil
+}
+
+func paletteRenameTag(m Model, arg string) (tea.Model, tea.Cmd) {
+	from, to, _ := strings.Cut(arg, " ")
+	return m.planRename(from, strings.TrimSpace(to))
+}
+
+func paletteTheme(m Model, arg string) (tea.Model, tea.Cmd) {
+	theme, ok := themePresets[strings.ToLower(arg)]
+	if !ok {
+		m.statusMsg = "Themes: " + strings.Join(themeNames(), ", ")
+		m.statusTime = time.Now()
+		return m, nil
+	}
+	m.cfg.Theme = theme
+	m.statusMsg = "Theme → " + strings.ToLower(arg)
+	m.statusTime = time.Now()
+	return m, nil
+}
+
+// paletteExport writes the tasks of the current view, as they appear in the
+// notes, to a markdown file in the working directory.
+func paletteExport(m Model, arg string) (tea.Model, tea.Cmd) {
+	path := arg
+	if path == "" {
+		path = "tasks-" + localToday().Format("2006-01-02") + ".md"
+	}
+
+	view := sidebarItems[m.activeView].label
+	lines := []string{"# " + view + " — " + localToday().Format("2006-01-02"), ""}
+	tasks := m.currentViewTasks()
+	for _, idx := range tasks {
+		lines = append(lines, strings.TrimLeft(m.allTasks[idx].RawLine, " \t"))
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		m.statusMsg = "Error: " + err.Error()
+		m.statusTime = time.Now()
+		return m, nil
+	}
+	m.statusMsg = fmt.Sprintf("Exported %d tasks → %s", len(tasks), path)
+	m.statusTime = time.Now()
+	return m, nil
+}
+
+func (m Model) renderPalette() string {
+	accent := lipgloss.Color(m.cfg.Theme.Accent)
+	muted := lipgloss.Color(m.cfg.Theme.Muted)
+	titleStyle := lipgloss.NewStyle().Foreground(accent).Bold(true)
+	nameStyle := lipgloss.NewStyle().Width(24)
+	descStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#999999")).Width(38)
+	keyStyle := lipgloss.NewStyle().Foreground(muted)
+	km := m.keymap()
+
+	input := m.input
+	input.Width = 56
+	rows := []string{titleStyle.Render("Command palette"), "", input.View(), ""}
+
+	matches := m.paletteMatches()
+	offset := max(0, m.paletteCursor-paletteLimit+1)
+	for i := offset; i < min(len(matches), offset+paletteLimit); i++ {
+		c := matches[i]
+		name := c.name
+		if c.arg != "" {
+			name += " " + c.arg
+		}
+		binding := ""
+		if c.action != "" {
+			binding = km.label(c.action)
+		}
+		row := nameStyle.Render(truncateText(name, 23)) + descStyle.Render(truncateText(c.desc, 37)) + keyStyle.Render(binding)
+		if i == m.paletteCursor {
+			row = lipgloss.NewStyle().Background(lipgloss.Color("#2a2a3a")).Foreground(accent).Bold(true).Render(row)
+		}
+		rows = append(rows, row)
+	}
+	if len(matches) == 0 {
+		rows = append(rows, keyStyle.Render("No matching command"))
+	}
+
+	rows = append(rows, "", keyStyle.Render("↑/↓ choose  tab complete  enter run  esc close"))
+
+	style := lipgloss.NewStyle().
+		Border(subtleBorder).
+		BorderForeground(accent).
+		Padding(1, 3).
+		Width(76)
+
+	return lipgloss.Place(m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		style.Render(strings.Join(rows, "\n")))
+}
@@ -6,8 +6,11 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 const (
@@ -35,27 +38,68 @@ var emojiToPriority = map[string]int{
 	"⏬": PriorityLowest,
 }
 
+// Checkbox characters understood by the parser. Obsidian Tasks treats
+// `[/]` as in progress; `[b]` marks a task as blocked.
+const (
+	StatusTodo       = ' '
+	StatusInProgress = '/'
+	StatusBlocked    = 'b'
+	StatusDone       = 'x'
+	StatusCancelled  = '-'
+)
+
 type Task struct {
 	Description    string
 	Done           bool
 	Cancelled      bool
+	Status         rune
 	Tags           []string
 	Priority       int
 	DueDate        time.Time
+	ScheduledDate  time.Time
+	StartDate      time.Time
+	Recurrence     string
 	CompletionDate time.Time
 	CancelledDate  time.Time
 	FilePath       string
 	LineNumber     int
 	RawLine        string
+	// Prefix is everything before the checkbox — indentation, blockquote
+	// markers and the list marker — kept as is when the line is rewritten.
+	Prefix string
+	// Heading is the nearest markdown heading above the task.
+	Heading string
+	// Section is the configured section heading the task was read from.
+	Section string
+	// Period is the week or month of the periodic note an undated task was
+	// read from; it is due over the whole period.
+	Period notePeriod
+	// HasDueDate is set when the task carries its own 📅 date; otherwise
+	// DueDate is NoteDate.
+	HasDueDate bool
+	// NoteDate is the day of the daily note, or the first day of the
+	// periodic note, the task was read from; zero for other notes.
+	NoteDate time.Time
 }
 
 var (
-	taskRe          = regexp.MustCompile(`^(\s*)-\s\[([ xX-])\]\s*(.*)$`)
-	tagRe           = regexp.MustCompile(`#[\w]+(?:/[\w]+)*`)
+	// taskRe matches a checkbox list item with any bullet or number, also
+	// inside blockquotes and callouts. The first group is the prefix up to
+	// the checkbox.
+	taskRe          = regexp.MustCompile(`^(\s*(?:>\s?)*\s*(?:[-*+]|\d+[.)])\s)\[([ xX\-/b])\]\s*(.*)$`)
 	dueDateRe       = regexp.MustCompile(`📅\s*(\d{4}-\d{2}-\d{2})`)
+	scheduledDateRe = regexp.MustCompile(`[⏳⌛]\s*(\d{4}-\d{2}-\d{2})`)
+	startDateRe     = regexp.MustCompile(`🛫\s*(\d{4}-\d{2}-\d{2})`)
+	recurrenceRe    = regexp.MustCompile(`🔁\s*([^📅⏳⌛🛫✅❌➕🔺⏫🔼🔽⏬#]*)`)
 	doneDateRe      = regexp.MustCompile(`✅\s*(\d{4}-\d{2}-\d{2})`)
 	cancelledDateRe = regexp.MustCompile(`❌\s*(\d{4}-\d{2}-\d{2})`)
 	priorityRe      = regexp.MustCompile(`[🔺⏫🔼🔽⏬]`)
+	headingRe       = regexp.MustCompile(`^#{1,6}\s`)
+	// linkRe matches the parts of a line a # inside links to a heading or
+	// anchor rather than tags: a [[wikilink]] and a markdown link's (target).
+	linkRe = regexp.MustCompile(`\[\[[^\]]*\]\]|\]\([^)]*\)`)
+	// metadataRe finds the first Obsidian Tasks field after the description.
+	metadataRe = regexp.MustCompile(`[🔺⏫🔼🔽⏬🔁🛫⏳⌛📅✅❌➕]`)
 )
 
 // ParseTask parses a single markdown line into a Task, if it matches.
@@ -68,10 +112,11 @@ func ParseTask(line string, filePath string, lineNumber int, noteDate time.Time)
 
 	done := m[2] == "x" || m[2] == "X"
 	cancelled := m[2] == "-"
+	status := []rune(strings.ToLower(m[2]))[0]
 	rest := m[3]
 
 	// Extract tags
-	tags := tagRe.FindAllString(rest, -1)
+	tags := extractTags(rest)
 
 	// Extract due date
 	var dueDate time.Time
@@ -80,10 +125,19 @@ func ParseTask(line string, filePath string, lineNumber int, noteDate time.Time)
 			dueDate = t
 		}
 	}
-	if dueDate.IsZero() {
+	hasDueDate := !dueDate.IsZero()
+	if !hasDueDate {
 		dueDate = noteDate
 	}
 
+	scheduledDate := parseDateToken(scheduledDateRe, rest)
+	startDate := parseDateToken(startDateRe, rest)
+
+	recurrence := ""
+	if rm := recurrenceRe.FindStringSubmatch(rest); rm != nil {
+		recurrence = strings.TrimSpace(rm[1])
+	}
+
 	// Extract completion date
 	var completionDate time.Time
 	if cm := doneDateRe.FindStringSubmatch(rest); cm != nil {
@@ -107,8 +161,11 @@ func ParseTask(line string, filePath string, lineNumber int, noteDate time.Time)
 	}
 
 	desc := rest
-	desc = tagRe.ReplaceAllString(desc, "")
+	desc = removeTags(desc)
 	desc = dueDateRe.ReplaceAllString(desc, "")
+	desc = scheduledDateRe.ReplaceAllString(desc, "")
+	desc = startDateRe.ReplaceAllString(desc, "")
+	desc = recurrenceRe.ReplaceAllString(desc, "")
 	desc = doneDateRe.ReplaceAllString(desc, "")
 	desc = cancelledDateRe.ReplaceAllString(desc, "")
 	desc = priorityRe.ReplaceAllString(desc, "")
@@ -118,21 +175,58 @@ func ParseTask(line string, filePath string, lineNumber int, noteDate time.Time)
 		Description:    desc,
 		Done:           done,
 		Cancelled:      cancelled,
+		Status:         status,
 		Tags:           tags,
 		Priority:       priority,
 		DueDate:        dueDate,
+		HasDueDate:     hasDueDate,
+		NoteDate:       noteDate,
+		ScheduledDate:  scheduledDate,
+		StartDate:      startDate,
+		Recurrence:     recurrence,
 		CompletionDate: completionDate,
 		CancelledDate:  cancelledDate,
 		FilePath:       filePath,
 		LineNumber:     lineNumber,
 		RawLine:        line,
+		Prefix:         m[1],
 	}, true
 }
 
+// parseDateToken returns the first YYYY-MM-DD date captured by re in s.
+func parseDateToken(re *regexp.Regexp, s string) time.Time {
+	if m := re.FindStringSubmatch(s); m != nil {
+		if t, err := time.Parse("2006-01-02", m[1]); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
 func (t Task) IsCompleted() bool {
 	return t.Done || t.Cancelled
 }
 
+// IsUndated reports whether the task has no 📅 date of its own and no note
+// date to fall back on. Only the inbox holds such tasks.
+func (t Task) IsUndated() bool {
+	return !t.HasDueDate && t.DueDate.IsZero()
+}
+
+// StatusChar returns the checkbox character for the task, deriving it from
+// Done/Cancelled when Status was never set.
+func (t Task) StatusChar() rune {
+	switch {
+	case t.Done:
+		return StatusDone
+	case t.Cancelled:
+		return StatusCancelled
+	case t.Status == 0 || t.Status == StatusDone || t.Status == StatusCancelled:
+		return StatusTodo
+	}
+	return t.Status
+}
+
 func (t Task) ClosedDate() time.Time {
 	if t.Done && !t.CompletionDate.IsZero() {
 		return t.CompletionDate
@@ -146,6 +240,16 @@ func (t Task) ClosedDate() time.Time {
 // ParseFile reads a daily note and extracts tasks within the given section.
 // If sectionHeading is empty, all tasks in the file are returned.
 func ParseFile(filePath string, noteDate time.Time, sectionHeading string) ([]Task, error) {
+	var sections sectionMatcher
+	if sectionHeading != "" {
+		sections.headings = []string{sectionHeading}
+	}
+	return parseFileSections(filePath, noteDate, sections)
+}
+
+// parseFileSections extracts the tasks under every heading sections matches.
+// A section runs until the next heading of the same or a higher level.
+func parseFileSections(filePath string, noteDate time.Time, sections sectionMatcher) ([]Task, error) {
 	f, err := os.Open(filePath)
 	if err != nil {
 		return nil, err
@@ -155,36 +259,41 @@ func ParseFile(filePath string, noteDate time.Time, sectionHeading string) ([]Ta
 	var tasks []Task
 	scanner := bufio.NewScanner(f)
 	lineNum := 0
-	inSection := sectionHeading == ""
-	sectionLevel := ""
-	if sectionHeading != "" {
-		for _, ch := range sectionHeading {
-			if ch == '#' {
-				sectionLevel += "#"
-			} else {
-				break
-			}
-		}
-	}
+	inSection := sections.all()
+	section := ""
+	sectionLevel := 0
 
+	heading := ""
+	var classifier lineClassifier
 	for scanner.Scan() {
 		lineNum++
 		line := scanner.Text()
+		if !classifier.isContent(line) {
+			continue
+		}
 		trimmed := strings.TrimSpace(line)
+		if headingRe.MatchString(trimmed) {
+			heading = trimmed
+		}
 
-		if sectionHeading != "" {
-			if trimmed == sectionHeading {
+		if !sections.all() {
+			if sections.matches(trimmed) {
 				inSection = true
+				section = trimmed
+				sectionLevel = headingLevel(trimmed)
 				continue
 			}
-			if inSection && strings.HasPrefix(trimmed, sectionLevel+" ") && !strings.HasPrefix(trimmed, sectionLevel+"#") {
+			if level := headingLevel(trimmed); inSection && level > 0 && level <= sectionLevel {
 				inSection = false
+				section = ""
 				continue
 			}
 		}
 
 		if inSection {
 			if t, ok := ParseTask(line, filePath, lineNum, noteDate); ok {
+				t.Heading = heading
+				t.Section = section
 				tasks = append(tasks, *t)
 			}
 		}
@@ -192,48 +301,84 @@ func ParseFile(filePath string, noteDate time.Time, sectionHeading string) ([]Ta
 	return tasks, scanner.Err()
 }
 
-// ScanDailyNotes scans the daily notes directory for tasks within the configured date range.
+// dailyNoteName is the daily note for day, relative to the daily notes
+// folder. Slashes in the format put notes in subfolders, such as
+// 2006/01-January/2006-01-02.
+func dailyNoteName(cfg Config, day time.Time) string {
+	return filepath.FromSlash(day.Format(cfg.Vault.DailyNoteFormat)) + ".md"
+}
+
+// vaultNotePath resolves a note named in the config against the vault. Like
+// Obsidian, the .md extension may be left off.
+func vaultNotePath(cfg Config, name string) string {
+	path := filepath.FromSlash(name)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(cfg.Vault.Path, path)
+	}
+	if filepath.Ext(path) != ".md" {
+		path += ".md"
+	}
+	return path
+}
+
+// ScanDailyNotes scans the daily notes directory, the weekly and monthly
+// notes and the inbox when configured, for tasks within the configured date
+// range. Inbox tasks are read whatever their date.
 func ScanDailyNotes(cfg Config) ([]Task, error) {
 	dir := filepath.Join(cfg.Vault.Path, cfg.Vault.DailyNotesDir)
 	now := time.Now()
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	start := today.AddDate(0, 0, -cfg.Tasks.LogbookDays)
 	end := today.AddDate(0, 0, cfg.Tasks.LookaheadDays)
+	sections, err := newSectionMatcher(cfg.Tasks)
+	if err != nil {
+		return nil, err
+	}
 
-	var allTasks []Task
-
+	var tasks []Task
 	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
-		filename := d.Format(cfg.Vault.DailyNoteFormat) + ".md"
-		fp := filepath.Join(dir, filename)
+		fp := filepath.Join(dir, dailyNoteName(cfg, d))
 		if _, err := os.Stat(fp); err != nil {
 			continue
 		}
-		tasks, err := ParseFile(fp, d, cfg.Tasks.SectionHeading)
+		parsed, err := parseFileSections(fp, d, sections)
 		if err != nil {
 			continue
 		}
-		for _, t := range tasks {
-			excluded := false
-			for _, tag := range t.Tags {
-				for _, ex := range cfg.Tasks.ExcludeTags {
-					if tag == ex || strings.HasPrefix(tag, ex+"/") {
-						excluded = true
-						break
-					}
-				}
-				if excluded {
-					break
-				}
-			}
-			if !excluded {
-				allTasks = append(allTasks, t)
-			}
+		tasks = append(tasks, parsed...)
+	}
+	for _, source := range cfg.periodicSources() {
+		tasks = append(tasks, scanPeriodicNotes(cfg, source, start, end, sections)...)
+	}
+	if cfg.Vault.InboxFile != "" {
+		// The inbox is one list, so every task in it is read, and the ones
+		// without a 📅 date stay undated.
+		if parsed, err := parseFileSections(vaultNotePath(cfg, cfg.Vault.InboxFile), time.Time{}, sectionMatcher{}); err == nil {
+			tasks = append(tasks, parsed...)
 		}
 	}
 
+	var allTasks []Task
+	for _, t := range tasks {
+		if !isExcluded(cfg, t) {
+			allTasks = append(allTasks, t)
+		}
+	}
 	return allTasks, nil
 }
 
+// isExcluded reports whether t carries one of the exclude_tags.
+func isExcluded(cfg Config, t Task) bool {
+	for _, tag := range t.Tags {
+		for _, ex := range cfg.Tasks.ExcludeTags {
+			if tagWithin(tag, ex) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func ToggleDone(task *Task) error {
 	lines, err := readLines(task.FilePath)
 	if err != nil {
@@ -247,24 +392,24 @@ func ToggleDone(task *Task) error {
 	line := lines[idx]
 	if task.IsCompleted() {
 		// Reopen: [x]/[-] → [ ], remove completion markers
-		line = strings.Replace(line, "[x]", "[ ]", 1)
-		line = strings.Replace(line, "[X]", "[ ]", 1)
-		line = strings.Replace(line, "[-]", "[ ]", 1)
+		line = setCheckbox(line, StatusTodo)
 		line = doneDateRe.ReplaceAllString(line, "")
 		line = cancelledDateRe.ReplaceAllString(line, "")
 		line = strings.TrimRight(line, " ")
 		task.Done = false
 		task.Cancelled = false
+		task.Status = StatusTodo
 		task.CompletionDate = time.Time{}
 		task.CancelledDate = time.Time{}
 	} else {
-		// Done: [ ] → [x], append ✅ date
-		line = strings.Replace(line, "[ ]", "[x]", 1)
+		// Done: [ ]/[/]/[b] → [x], append ✅ date
+		line = setCheckbox(line, StatusDone)
 		now := time.Now()
 		todayLocal := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 		line = line + " ✅ " + todayLocal.Format("2006-01-02")
 		task.Done = true
 		task.Cancelled = false
+		task.Status = StatusDone
 		task.CompletionDate = todayLocal
 		task.CancelledDate = time.Time{}
 	}
@@ -285,9 +430,7 @@ func CancelTask(task *Task) error {
 	}
 
 	line := lines[idx]
-	line = strings.Replace(line, "[ ]", "[-]", 1)
-	line = strings.Replace(line, "[x]", "[-]", 1)
-	line = strings.Replace(line, "[X]", "[-]", 1)
+	line = setCheckbox(line, StatusCancelled)
 	line = doneDateRe.ReplaceAllString(line, "")
 	line = cancelledDateRe.ReplaceAllString(line, "")
 	now := time.Now()
@@ -298,27 +441,84 @@ func CancelTask(task *Task) error {
 	lines[idx] = line
 	task.Done = false
 	task.Cancelled = true
+	task.Status = StatusCancelled
 	task.CompletionDate = time.Time{}
 	task.CancelledDate = todayLocal
 	task.RawLine = line
 	return writeLines(task.FilePath, lines)
 }
 
-func buildTaskLine(description string, tags []string, priority int, dueDate time.Time, done bool, cancelled bool, completionDate time.Time, cancelledDate time.Time) string {
-	status := "[ ]"
-	if done {
-		status = "[x]"
-	} else if cancelled {
-		status = "[-]"
+// SetStatus rewrites the task's checkbox character. Moving into done stamps
+// today's ✅ date; any other status drops completion and cancellation dates.
+func SetStatus(task *Task, status rune) error {
+	lines, err := readLines(task.FilePath)
+	if err != nil {
+		return err
+	}
+	idx := task.LineNumber - 1
+	if err := verifyLine(lines, idx, task.RawLine); err != nil {
+		return err
 	}
 
+	line := setCheckbox(lines[idx], status)
+	line = doneDateRe.ReplaceAllString(line, "")
+	line = cancelledDateRe.ReplaceAllString(line, "")
+	line = strings.TrimRight(line, " ")
+	task.CompletionDate = time.Time{}
+	task.CancelledDate = time.Time{}
+	if status == StatusDone {
+		todayLocal := localToday()
+		line = line + " ✅ " + todayLocal.Format("2006-01-02")
+		task.CompletionDate = todayLocal
+	}
+
+	lines[idx] = line
+	task.Done = status == StatusDone
+	task.Cancelled = status == StatusCancelled
+	task.Status = status
+	task.RawLine = line
+	return writeLines(task.FilePath, lines)
+}
+
+// setCheckbox replaces the character inside the task's checkbox, leaving the
+// rest of the line untouched.
+func setCheckbox(line string, status rune) string {
+	loc := taskRe.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return line
+	}
+	return line[:loc[4]] + string(status) + line[loc[5]:]
+}
+
+func buildTaskLine(description string, tags []string, priority int, dueDate time.Time, done bool, cancelled bool, completionDate time.Time, cancelledDate time.Time) string {
+	return formatTaskLine(Task{
+		Description:    description,
+		Done:           done,
+		Cancelled:      cancelled,
+		Tags:           tags,
+		Priority:       priority,
+		DueDate:        dueDate,
+		HasDueDate:     !dueDate.IsZero(),
+		CompletionDate: completionDate,
+		CancelledDate:  cancelledDate,
+	})
+}
+
+// formatTaskLine renders a task back into Obsidian Tasks markdown, emitting
+// metadata in the plugin's canonical order after the task's original prefix.
+func formatTaskLine(t Task) string {
 	var b strings.Builder
-	b.WriteString("- ")
-	b.WriteString(status)
-	b.WriteString(" ")
-	b.WriteString(strings.TrimSpace(description))
+	if t.Prefix != "" {
+		b.WriteString(t.Prefix)
+	} else {
+		b.WriteString("- ")
+	}
+	b.WriteString("[")
+	b.WriteRune(t.StatusChar())
+	b.WriteString("] ")
+	b.WriteString(strings.TrimSpace(t.Description))
 
-	for _, tag := range tags {
+	for _, tag := range t.Tags {
 		if strings.TrimSpace(tag) == "" {
 			continue
 		}
@@ -326,40 +526,64 @@ func buildTaskLine(description string, tags []string, priority int, dueDate time
 		b.WriteString(tag)
 	}
 
-	if emoji, ok := priorityEmojis[priority]; ok {
+	if emoji, ok := priorityEmojis[t.Priority]; ok {
 		b.WriteString(" ")
 		b.WriteString(emoji)
 	}
 
-	if !dueDate.IsZero() {
+	if r := strings.TrimSpace(t.Recurrence); r != "" {
+		b.WriteString(" 🔁 ")
+		b.WriteString(r)
+	}
+
+	if !t.StartDate.IsZero() {
+		b.WriteString(" 🛫 ")
+		b.WriteString(t.StartDate.Format("2006-01-02"))
+	}
+
+	if !t.ScheduledDate.IsZero() {
+		b.WriteString(" ⏳ ")
+		b.WriteString(t.ScheduledDate.Format("2006-01-02"))
+	}
+
+	// A task due with its note keeps relying on the note's date rather than
+	// being pinned to it.
+	if t.HasDueDate {
 		b.WriteString(" 📅 ")
-		b.WriteString(dueDate.Format("2006-01-02"))
+		b.WriteString(t.DueDate.Format("2006-01-02"))
 	}
 
-	if done && !completionDate.IsZero() {
+	if t.Done && !t.CompletionDate.IsZero() {
 		b.WriteString(" ✅ ")
-		b.WriteString(completionDate.Format("2006-01-02"))
+		b.WriteString(t.CompletionDate.Format("2006-01-02"))
 	}
 
-	if cancelled && !cancelledDate.IsZero() {
+	if t.Cancelled && !t.CancelledDate.IsZero() {
 		b.WriteString(" ❌ ")
-		b.WriteString(cancelledDate.Format("2006-01-02"))
+		b.WriteString(t.CancelledDate.Format("2006-01-02"))
 	}
 
 	return b.String()
 }
 
-func appendTaskLine(cfg Config, dueDate time.Time, taskLine string) error {
-	dir := filepath.Join(cfg.Vault.Path, cfg.Vault.DailyNotesDir)
-	if err := os.MkdirAll(dir, 0755); err != nil {
+// appendTaskLine inserts taskLine at the top of section in the daily note
+// for dueDate, creating the note or the section when missing. New notes are
+// made from template_path when it is set.
+func appendTaskLine(cfg Config, dueDate time.Time, section, taskLine string) error {
+	fp := filepath.Join(cfg.Vault.Path, cfg.Vault.DailyNotesDir, dailyNoteName(cfg, dueDate))
+	if err := os.MkdirAll(filepath.Dir(fp), 0755); err != nil {
 		return err
 	}
 
-	filename := dueDate.Format(cfg.Vault.DailyNoteFormat) + ".md"
-	fp := filepath.Join(dir, filename)
-
-	// If file doesn't exist, create with template
-	if _, err := os.Stat(fp); os.IsNotExist(err) {
+	var lines []string
+	_, err := os.Stat(fp)
+	switch {
+	case os.IsNotExist(err) && cfg.Vault.TemplatePath != "":
+		if lines, err = dailyNoteTemplate(cfg, dueDate); err != nil {
+			return err
+		}
+	case os.IsNotExist(err):
+		// No template: a minimal note with the task under its heading
 		content := fmt.Sprintf(`---
 created: %s
 ---
@@ -369,43 +593,76 @@ created: %s
 %s
 
 ---
-`, dueDate.Format("2006-01-02"), cfg.Tasks.SectionHeading, taskLine)
+`, dueDate.Format("2006-01-02"), section, taskLine)
 		return os.WriteFile(fp, []byte(content), 0644)
+	default:
+		// The note exists: insert under the section heading
+		if lines, err = readLines(fp); err != nil {
+			return err
+		}
 	}
 
-	// File exists — insert under section heading
-	lines, err := readLines(fp)
-	if err != nil {
-		return err
+	lines, ok := insertUnderHeading(lines, section, taskLine)
+	if !ok {
+		// Heading not found, append at end
+		lines = append(lines, "", section, "", taskLine)
 	}
+	return writeLines(fp, lines)
+}
 
-	insertIdx := -1
+// insertUnderHeading inserts taskLine at the top of section's content. ok is
+// false when lines have no such heading.
+func insertUnderHeading(lines []string, section, taskLine string) ([]string, bool) {
 	for i, l := range lines {
-		if strings.TrimSpace(l) == cfg.Tasks.SectionHeading {
-			insertIdx = i + 1
-			// Skip blank lines after heading
-			for insertIdx < len(lines) && strings.TrimSpace(lines[insertIdx]) == "" {
-				insertIdx++
-			}
-			break
+		if strings.TrimSpace(l) != section {
+			continue
 		}
+		insertIdx := i + 1
+		// Skip blank lines after heading
+		for insertIdx < len(lines) && strings.TrimSpace(lines[insertIdx]) == "" {
+			insertIdx++
+		}
+		return append(lines[:insertIdx], append([]string{taskLine}, lines[insertIdx:]...)...), true
 	}
+	return lines, false
+}
 
-	if insertIdx == -1 {
-		// Heading not found, append at end
-		lines = append(lines, "", cfg.Tasks.SectionHeading, "", taskLine)
-	} else {
-		// Insert at position
-		lines = append(lines[:insertIdx], append([]string{taskLine}, lines[insertIdx:]...)...)
+// appendInboxTask adds an undated task to the inbox file: under section when
+// the inbox has that heading, otherwise at the end.
+func appendInboxTask(cfg Config, section, taskLine string) error {
+	if cfg.Vault.InboxFile == "" {
+		return fmt.Errorf("task has no date and no inbox_file is set")
+	}
+	fp := vaultNotePath(cfg, cfg.Vault.InboxFile)
+	if err := os.MkdirAll(filepath.Dir(fp), 0755); err != nil {
+		return err
+	}
+	if _, err := os.Stat(fp); os.IsNotExist(err) {
+		return os.WriteFile(fp, []byte(taskLine+"\n"), 0644)
+	}
+	lines, err := readLines(fp)
+	if err != nil {
+		return err
+	}
+	if section == "" {
+		return writeLines(fp, append(lines, taskLine))
+	}
+	lines, ok := insertUnderHeading(lines, section, taskLine)
+	if !ok {
+		lines = append(lines, taskLine)
 	}
-
 	return writeLines(fp, lines)
 }
 
-// CreateTask appends a new task to the appropriate daily note file.
-func CreateTask(cfg Config, description string, dueDate time.Time, priority int) error {
-	taskLine := buildTaskLine(description, nil, priority, dueDate, false, false, time.Time{}, time.Time{})
-	return appendTaskLine(cfg, dueDate, taskLine)
+// CreateTask appends a new task to the daily note for its due date, under
+// the section its tags map to, spelling the date out. Undated tasks go to
+// the inbox.
+func CreateTask(cfg Config, task Task) error {
+	task.HasDueDate = !task.DueDate.IsZero()
+	if task.IsUndated() {
+		return appendInboxTask(cfg, cfg.Tasks.taskSection(task), formatTaskLine(task))
+	}
+	return appendTaskLine(cfg, task.DueDate, cfg.Tasks.taskSection(task), formatTaskLine(task))
 }
 
 func CreateFollowUpTask(cfg Config, task Task) (time.Time, error) {
@@ -419,13 +676,41 @@ func CreateFollowUpTask(cfg Config, task Task) (time.Time, error) {
 	}
 
 	taskLine := buildTaskLine(description, task.Tags, task.Priority, followUpDate, false, false, time.Time{}, time.Time{})
-	if err := appendTaskLine(cfg, followUpDate, taskLine); err != nil {
+	if err := appendTaskLine(cfg, followUpDate, cfg.Tasks.taskSection(task), taskLine); err != nil {
 		return time.Time{}, err
 	}
 
 	return followUpDate, nil
 }
 
+// ClearDueDate removes the task's 📅 date, leaving it due with its note, or
+// undated outside daily and periodic notes.
+func ClearDueDate(task *Task) error {
+	lines, err := readLines(task.FilePath)
+	if err != nil {
+		return err
+	}
+	idx := task.LineNumber - 1
+	if err := verifyLine(lines, idx, task.RawLine); err != nil {
+		return err
+	}
+
+	line := lines[idx]
+	if loc := dueDateRe.FindStringIndex(line); loc != nil {
+		start := loc[0]
+		if start > 0 && line[start-1] == ' ' {
+			start--
+		}
+		line = line[:start] + line[loc[1]:]
+	}
+
+	lines[idx] = line
+	task.RawLine = line
+	task.DueDate = task.NoteDate
+	task.HasDueDate = false
+	return writeLines(task.FilePath, lines)
+}
+
 func RescheduleTask(task *Task, newDate time.Time) error {
 	lines, err := readLines(task.FilePath)
 	if err != nil {
@@ -447,6 +732,7 @@ func RescheduleTask(task *Task, newDate time.Time) error {
 	lines[idx] = line
 	task.RawLine = line
 	task.DueDate = newDate
+	task.HasDueDate = true
 	return writeLines(task.FilePath, lines)
 }
 
@@ -500,6 +786,248 @@ func UpdateTaskLine(task *Task, newLine string) error {
 	return writeLines(task.FilePath, lines)
 }
 
+// EditTaskTags adds and removes tags on a task's line in place, leaving the
+// rest of the line untouched. It reports whether the line changed.
+func EditTaskTags(task *Task, add, remove []string) (bool, error) {
+	lines, err := readLines(task.FilePath)
+	if err != nil {
+		return false, err
+	}
+	idx := task.LineNumber - 1
+	if err := verifyLine(lines, idx, task.RawLine); err != nil {
+		return false, err
+	}
+
+	line := editTagsInLine(lines[idx], func(tag string) string {
+		for _, r := range remove {
+			if strings.EqualFold(tag, r) {
+				return ""
+			}
+		}
+		return tag
+	})
+	line = addTagsToLine(line, add)
+	if line == lines[idx] {
+		return false, nil
+	}
+
+	lines[idx] = line
+	task.RawLine = line
+	if m := taskRe.FindStringSubmatch(line); m != nil {
+		task.Tags = extractTags(m[3])
+	}
+	return true, writeLines(task.FilePath, lines)
+}
+
+// editTagsInLine passes every tag token on line through fn, which returns
+// the replacement or "" to drop the tag along with one adjacent space.
+func editTagsInLine(line string, fn func(tag string) string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range findTags(line) {
+		tag := line[loc[0]:loc[1]]
+		replacement := fn(tag)
+		if replacement == tag {
+			continue
+		}
+		start, end := loc[0], loc[1]
+		if replacement == "" {
+			if start > last && line[start-1] == ' ' {
+				start--
+			} else if end < len(line) && line[end] == ' ' {
+				end++
+			}
+		}
+		b.WriteString(line[last:start])
+		b.WriteString(replacement)
+		last = end
+	}
+	b.WriteString(line[last:])
+	return b.String()
+}
+
+// findTags returns the byte ranges of the tags in s, following Obsidian's
+// grammar: a # at the start or after whitespace, ( or [, then Unicode
+// letters, digits, marks, -, _ and /, with at least one character that isn't
+// a digit so issue references like #123 aren't tags. Headings and anchors in
+// [[#links]] and [links](#targets) aren't tags either.
+func findTags(s string) [][2]int {
+	var locs [][2]int
+	links := linkRe.FindAllStringIndex(s, -1)
+	for i := 0; i < len(s); {
+		j := strings.IndexByte(s[i:], '#')
+		if j < 0 {
+			break
+		}
+		start := i + j
+		i = start + 1
+		if slices.ContainsFunc(links, func(l []int) bool { return start > l[0] && start < l[1] }) {
+			continue
+		}
+		if start > 0 {
+			prev, _ := utf8.DecodeLastRuneInString(s[:start])
+			if !isTagBoundary(prev) {
+				continue
+			}
+		}
+		end := start + 1
+		for end < len(s) {
+			r, size := utf8.DecodeRuneInString(s[end:])
+			if !isTagRune(r) {
+				break
+			}
+			end += size
+		}
+		for end > start+1 && s[end-1] == '/' {
+			end--
+		}
+		if !strings.ContainsFunc(s[start+1:end], func(r rune) bool { return !unicode.IsDigit(r) }) {
+			continue
+		}
+		locs = append(locs, [2]int{start, end})
+		i = end
+	}
+	return locs
+}
+
+func isTagRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) || r == '-' || r == '_' || r == '/'
+}
+
+func isTagBoundary(r rune) bool {
+	return unicode.IsSpace(r) || r == '(' || r == '['
+}
+
+// extractTags returns the tags in s in order.
+func extractTags(s string) []string {
+	var tags []string
+	for _, loc := range findTags(s) {
+		tags = append(tags, s[loc[0]:loc[1]])
+	}
+	return tags
+}
+
+// removeTags drops the tags from s, leaving the text around them.
+func removeTags(s string) string {
+	return editTagsInLine(s, func(string) string { return "" })
+}
+
+// isValidTag reports whether tag is exactly one tag, # included.
+func isValidTag(tag string) bool {
+	locs := findTags(tag)
+	return len(locs) == 1 && locs[0] == [2]int{0, len(tag)}
+}
+
+// tagWithin reports whether tag is parent or nested under it, ignoring case
+// as Obsidian does.
+func tagWithin(tag, parent string) bool {
+	n := foldPrefixLen(tag, parent)
+	return n == len(tag) || n >= 0 && tag[n] == '/'
+}
+
+// foldPrefixLen returns the length in bytes of the start of s that matches
+// prefix ignoring case, or -1. Case folding can change a rune's length, as
+// with the Kelvin sign and k, so the length can differ from prefix's.
+func foldPrefixLen(s, prefix string) int {
+	n := 0
+	for _, want := range prefix {
+		if n >= len(s) {
+			return -1
+		}
+		r, size := utf8.DecodeRuneInString(s[n:])
+		if r != want && !strings.EqualFold(string(r), string(want)) {
+			return -1
+		}
+		n += size
+	}
+	return n
+}
+
+// addTagsToLine inserts the tags the line doesn't carry yet just before its
+// first metadata field, or at the end when it has none.
+func addTagsToLine(line string, tags []string) string {
+	existing := extractTags(line)
+	var missing []string
+	for _, tag := range tags {
+		found := false
+		for _, e := range existing {
+			if strings.EqualFold(e, tag) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, tag)
+			existing = append(existing, tag)
+		}
+	}
+	if len(missing) == 0 {
+		return line
+	}
+
+	insert := strings.Join(missing, " ")
+	if loc := metadataRe.FindStringIndex(line); loc != nil && loc[0] > 0 {
+		before := strings.TrimRight(line[:loc[0]], " ")
+		return before + " " + insert + " " + line[loc[0]:]
+	}
+	return strings.TrimRight(line, " ") + " " + insert
+}
+
+// TaskContext returns the lines below a task in its note: first its indented
+// children, then up to limit further lines, stopping at the next heading.
+func TaskContext(task Task, limit int) ([]string, error) {
+	lines, err := readLines(task.FilePath)
+	if err != nil {
+		return nil, err
+	}
+	idx := task.LineNumber - 1
+	if err := verifyLine(lines, idx, task.RawLine); err != nil {
+		return nil, err
+	}
+
+	// Children are indented further inside the same blockquote or callout.
+	depth, indent := quoteIndent(task.RawLine)
+	var context []string
+	i := idx + 1
+	for ; i < len(lines); i++ {
+		d, n := quoteIndent(lines[i])
+		if n < 0 || d != depth || n <= indent {
+			break
+		}
+		context = append(context, lines[i])
+	}
+	for extra := 0; i < len(lines) && extra < limit; i, extra = i+1, extra+1 {
+		if headingRe.MatchString(strings.TrimSpace(lines[i])) {
+			break
+		}
+		context = append(context, lines[i])
+	}
+	for len(context) > 0 && strings.TrimSpace(context[len(context)-1]) == "" {
+		context = context[:len(context)-1]
+	}
+	return context, nil
+}
+
+// quoteIndent returns how many blockquote markers a line starts with and how
+// far the text after them is indented, or -1 when nothing follows them.
+func quoteIndent(line string) (depth, indent int) {
+	rest := strings.TrimLeft(line, " \t")
+	if !strings.HasPrefix(rest, ">") {
+		rest = line
+	}
+	for strings.HasPrefix(rest, ">") {
+		depth++
+		rest = strings.TrimPrefix(rest[1:], " ")
+		if trimmed := strings.TrimLeft(rest, " \t"); strings.HasPrefix(trimmed, ">") {
+			rest = trimmed
+		}
+	}
+	if strings.TrimSpace(rest) == "" {
+		return depth, -1
+	}
+	return depth, len(rest) - len(strings.TrimLeft(rest, " \t"))
+}
+
 func readLines(path string) ([]string, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -89,3 +91,570 @@ func TestParseTaskRecognizesCancelledStatus(t *testing.T) {
 		t.Fatalf("unexpected cancelled date: %s", task.CancelledDate.Format("2006-01-02"))
 	}
 }
+
+func TestSetStatusRewritesCheckboxAndDates(t *testing.T) {
+	dir := t.TempDir()
+	notePath := filepath.Join(dir, "note.md")
+	line := "- [ ] Review contract #legal 📅 2026-03-09"
+	if err := os.WriteFile(notePath, []byte("## Tasks\n"+line+"\n"), 0o644); err != nil {
+		t.Fatalf("write note: %v", err)
+	}
+
+	task, ok := ParseTask(line, notePath, 2, time.Time{})
+	if !ok {
+		t.Fatal("expected line to be parsed as task")
+	}
+
+	if err := SetStatus(task, StatusInProgress); err != nil {
+		t.Fatalf("SetStatus in progress: %v", err)
+	}
+	if task.RawLine != "- [/] Review contract #legal 📅 2026-03-09" {
+		t.Fatalf("unexpected in-progress line: %s", task.RawLine)
+	}
+
+	if err := SetStatus(task, StatusDone); err != nil {
+		t.Fatalf("SetStatus done: %v", err)
+	}
+	expected := "- [x] Review contract #legal 📅 2026-03-09 ✅ " + localToday().Format("2006-01-02")
+	if task.RawLine != expected || !task.Done {
+		t.Fatalf("unexpected done line: %s", task.RawLine)
+	}
+
+	if err := SetStatus(task, StatusBlocked); err != nil {
+		t.Fatalf("SetStatus blocked: %v", err)
+	}
+	if task.RawLine != "- [b] Review contract #legal 📅 2026-03-09" || task.Done {
+		t.Fatalf("expected completion date to be removed, got: %s", task.RawLine)
+	}
+
+	content, err := os.ReadFile(notePath)
+	if err != nil {
+		t.Fatalf("read note: %v", err)
+	}
+	if !strings.Contains(string(content), task.RawLine) {
+		t.Fatalf("expected file to contain %q, got:\n%s", task.RawLine, content)
+	}
+
+	reparsed, ok := ParseTask(task.RawLine, notePath, 2, time.Time{})
+	if !ok || reparsed.Status != StatusBlocked {
+		t.Fatalf("expected blocked status to round-trip, got %+v", reparsed)
+	}
+}
+
+func TestParseTaskReadsScheduledStartAndRecurrence(t *testing.T) {
+	line := "- [ ] Water plants #home 🔁 every week on Sunday 🛫 2026-03-01 ⏳ 2026-03-02 📅 2026-03-03"
+	task, ok := ParseTask(line, "note.md", 1, time.Time{})
+	if !ok {
+		t.Fatal("expected line to be parsed as task")
+	}
+
+	if task.Description != "Water plants" {
+		t.Fatalf("unexpected description: %q", task.Description)
+	}
+	if task.Recurrence != "every week on Sunday" {
+		t.Fatalf("unexpected recurrence: %q", task.Recurrence)
+	}
+	if task.StartDate.Format("2006-01-02") != "2026-03-01" || task.ScheduledDate.Format("2006-01-02") != "2026-03-02" {
+		t.Fatalf("unexpected start/scheduled dates: %s %s", task.StartDate, task.ScheduledDate)
+	}
+
+	if rebuilt := formatTaskLine(*task); rebuilt != line {
+		t.Fatalf("expected line to round-trip\nexpected: %s\nactual:   %s", line, rebuilt)
+	}
+}
+
+func TestFormatTaskLineWritesOnlyATasksOwnDueDate(t *testing.T) {
+	monday := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	week := notePeriod{Kind: "week", Start: monday, End: monday.AddDate(0, 0, 6)}
+	for _, line := range []string{
+		"- [ ] Plan the week",
+		"- [ ] Due on monday 📅 2026-03-02",
+	} {
+		task, ok := ParseTask(line, "2026-W10.md", 1, monday)
+		if !ok {
+			t.Fatalf("expected %q to be parsed as task", line)
+		}
+		task.Period = week
+		if rebuilt := formatTaskLine(*task); rebuilt != line {
+			t.Fatalf("expected line to round-trip\nexpected: %s\nactual:   %s", line, rebuilt)
+		}
+	}
+}
+
+func TestIsUndatedFallsBackToTheNoteDate(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	for _, c := range []struct {
+		line     string
+		noteDate time.Time
+		undated  bool
+	}{
+		{"- [ ] Inbox idea", time.Time{}, true},
+		{"- [ ] Inbox idea 📅 2026-03-05", time.Time{}, false},
+		{"- [ ] Daily task", day, false},
+		{"- [ ] Daily task 📅 2026-03-05", day, false},
+	} {
+		task, ok := ParseTask(c.line, "note.md", 1, c.noteDate)
+		if !ok {
+			t.Fatalf("expected %q to be parsed as task", c.line)
+		}
+		if task.IsUndated() != c.undated {
+			t.Fatalf("%q with note date %v: IsUndated() = %v, want %v", c.line, c.noteDate, task.IsUndated(), c.undated)
+		}
+		if task.HasDueDate != strings.Contains(c.line, "📅") {
+			t.Fatalf("%q: HasDueDate = %v", c.line, task.HasDueDate)
+		}
+	}
+}
+
+func TestEditTaskTagsKeepsMetadataInPlace(t *testing.T) {
+	dir := t.TempDir()
+	notePath := filepath.Join(dir, "note.md")
+	line := "  - [ ] Review #legal/contracts contract #legal ⏫ 🔁 every week 📅 2026-03-09"
+	if err := os.WriteFile(notePath, []byte("## Tasks\n"+line+"\n"), 0o644); err != nil {
+		t.Fatalf("write note: %v", err)
+	}
+	task, ok := ParseTask(line, notePath, 2, time.Time{})
+	if !ok {
+		t.Fatal("expected line to be parsed as task")
+	}
+
+	changed, err := EditTaskTags(task, []string{"#work", "#Legal/Contracts"}, []string{"#legal"})
+	if err != nil || !changed {
+		t.Fatalf("EditTaskTags: changed=%v err=%v", changed, err)
+	}
+	want := "  - [ ] Review #legal/contracts contract #work ⏫ 🔁 every week 📅 2026-03-09"
+	if task.RawLine != want {
+		t.Fatalf("unexpected line:\n got %q\nwant %q", task.RawLine, want)
+	}
+
+	changed, err = EditTaskTags(task, []string{"#work"}, nil)
+	if err != nil || changed {
+		t.Fatalf("expected adding an existing tag to be a no-op, changed=%v err=%v", changed, err)
+	}
+
+	if got := addTagsToLine("- [ ] Plain task", []string{"#a", "#b"}); got != "- [ ] Plain task #a #b" {
+		t.Fatalf("expected tags appended without metadata, got %q", got)
+	}
+}
+
+func TestParseTaskFollowsObsidianTagGrammar(t *testing.T) {
+	line := "- [ ] Revisar #café #reunião/semanal #follow-up #1on1-mgr fix #123 see a.com/#anchor (#inline) 📅 2026-03-18"
+	task, ok := ParseTask(line, "note.md", 1, time.Time{})
+	if !ok {
+		t.Fatal("expected line to be parsed as task")
+	}
+	want := []string{"#café", "#reunião/semanal", "#follow-up", "#1on1-mgr", "#inline"}
+	if strings.Join(task.Tags, " ") != strings.Join(want, " ") {
+		t.Fatalf("unexpected tags: got %q want %q", task.Tags, want)
+	}
+	if task.Description != "Revisar fix #123 see a.com/#anchor ()" {
+		t.Fatalf("unexpected description %q", task.Description)
+	}
+
+	line = "- [ ] review [[#Goals]] and [[Plan#Scope]], see [link](#anchor) ([#tag]) #real"
+	task, _ = ParseTask(line, "note.md", 1, time.Time{})
+	if strings.Join(task.Tags, " ") != "#tag #real" {
+		t.Fatalf("expected link headings and anchors not to be tags, got %q", task.Tags)
+	}
+	if task.Description != "review [[#Goals]] and [[Plan#Scope]], see [link](#anchor) ([])" {
+		t.Fatalf("unexpected description %q", task.Description)
+	}
+
+	cfg := testConfigWithTempVault(t)
+	cfg.Tasks.ExcludeTags = []string{"#habit"}
+	today := localToday()
+	writeDailyNote(t, cfg, today, []string{
+		"- [ ] Stretch #Habit/Morning",
+		"- [ ] Meditate #HABIT",
+		"- [ ] Habitat survey #habitat",
+	})
+	tasks, err := ScanDailyNotes(cfg)
+	if err != nil {
+		t.Fatalf("scan daily notes: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].Description != "Habitat survey" {
+		t.Fatalf("expected only the #habitat task to survive exclusion, got %+v", tasks)
+	}
+}
+
+func TestTagMatchingFoldsCaseRuneByRune(t *testing.T) {
+	// The Kelvin sign folds to k but is three bytes long; ſ folds to s in two.
+	for _, c := range []struct {
+		tag, parent string
+		within      bool
+		renamed     string
+	}{
+		{"#\u212Aey/sub", "#key", true, "#lock/sub"},
+		{"#key/sub", "#\u212Aey", true, "#lock/sub"},
+		{"#\u212Aey", "#KEY", true, "#lock"},
+		{"#\u212Aeys", "#key", false, "#\u212Aeys"},
+		{"#ſtuff/a", "#STUFF", true, "#lock/a"},
+		{"#stuff", "#ſtuff/a", false, "#stuff"},
+	} {
+		if got := tagWithin(c.tag, c.parent); got != c.within {
+			t.Fatalf("tagWithin(%q, %q) = %v", c.tag, c.parent, got)
+		}
+		if got := renameTag(c.tag, c.parent, "#lock"); got != c.renamed {
+			t.Fatalf("renameTag(%q, %q) = %q, want %q", c.tag, c.parent, got, c.renamed)
+		}
+	}
+}
+
+func TestParseFileSkipsCodeCommentsAndFrontmatter(t *testing.T) {
+	note := strings.Join([]string{
+		"---",
+		"- [ ] frontmatter",
+		"---",
+		"## Tasks",
+		"- [ ] plain",
+		"\t- [ ] nested under plain",
+		"```markdown",
+		"## Example",
+		"- [ ] fenced",
+		"```",
+		"> [!todo] Callout",
+		"> - [ ] in callout",
+		"> ~~~",
+		"> - [ ] fenced in callout",
+		"> ~~~",
+		"1. [ ] numbered",
+		"2) [x] numbered done",
+		"%% - [ ] one-line comment %%",
+		"%%",
+		"- [ ] block comment",
+		"%%",
+		"<!-- - [ ] html comment",
+		"- [ ] still html comment -->",
+		"- [ ] after comments %% with a note %%",
+		"",
+		"Example paragraph:",
+		"",
+		"    - [ ] indented code",
+		"",
+		"- [ ] last",
+	}, "\n")
+	path := filepath.Join(t.TempDir(), "note.md")
+	if err := os.WriteFile(path, []byte(note), 0o644); err != nil {
+		t.Fatalf("write note: %v", err)
+	}
+
+	tasks, err := ParseFile(path, time.Time{}, "## Tasks")
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	var got []string
+	for _, task := range tasks {
+		got = append(got, task.Description)
+	}
+	want := []string{
+		"plain", "nested under plain", "in callout", "numbered", "numbered done",
+		"after comments %% with a note %%", "last",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("unexpected tasks:\n got %q\nwant %q", got, want)
+	}
+	if !tasks[4].Done || tasks[2].LineNumber != 12 {
+		t.Fatalf("expected numbered done task and callout on line 12, got %+v %+v", tasks[4], tasks[2])
+	}
+}
+
+func TestWritesPreserveListAndQuotePrefixes(t *testing.T) {
+	prefixes := []string{"* ", "+ ", "\t1. ", "2) ", "> - ", "> > * ", "  - "}
+	var lines []string
+	for i, prefix := range prefixes {
+		lines = append(lines, fmt.Sprintf("%s[ ] Task %d 📅 2026-03-18", prefix, i))
+	}
+	path := filepath.Join(t.TempDir(), "note.md")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatalf("write note: %v", err)
+	}
+
+	tasks, err := ParseFile(path, time.Time{}, "")
+	if err != nil || len(tasks) != len(prefixes) {
+		t.Fatalf("expected %d tasks, got %d (err %v)", len(prefixes), len(tasks), err)
+	}
+	newDue := time.Date(2026, time.March, 20, 0, 0, 0, 0, time.Local)
+	for i := range tasks {
+		task := &tasks[i]
+		if task.Prefix != prefixes[i] {
+			t.Fatalf("task %d: prefix %q, want %q", i, task.Prefix, prefixes[i])
+		}
+		steps := []struct {
+			name string
+			run  func() error
+		}{
+			{"toggle", func() error { return ToggleDone(task) }},
+			{"reopen", func() error { return ToggleDone(task) }},
+			{"reschedule", func() error { return RescheduleTask(task, newDue) }},
+			{"priority", func() error { return SetPriority(task, PriorityHigh) }},
+			{"tags", func() error { _, err := EditTaskTags(task, []string{"#work"}, nil); return err }},
+			{"edit", func() error {
+				edited := *task
+				edited.Description = fmt.Sprintf("Edited %d", i)
+				return UpdateTaskLine(task, formatTaskLine(edited))
+			}},
+			{"cancel", func() error { return CancelTask(task) }},
+		}
+		for _, step := range steps {
+			if err := step.run(); err != nil {
+				t.Fatalf("task %d %s: %v", i, step.name, err)
+			}
+			if !strings.HasPrefix(task.RawLine, prefixes[i]+"[") {
+				t.Fatalf("task %d %s lost its prefix: %q", i, step.name, task.RawLine)
+			}
+		}
+	}
+
+	reread, err := ParseFile(path, time.Time{}, "")
+	if err != nil || len(reread) != len(prefixes) {
+		t.Fatalf("expected %d tasks after writes, got %d (err %v)", len(prefixes), len(reread), err)
+	}
+	for i, task := range reread {
+		want := fmt.Sprintf("%s[-] Edited %d #work ⏫ 📅 2026-03-20 ❌ ", prefixes[i], i)
+		if !strings.HasPrefix(task.RawLine, want) || !task.Cancelled {
+			t.Fatalf("task %d: got %q, want prefix %q", i, task.RawLine, want)
+		}
+	}
+}
+
+func TestSectionsReadSeveralHeadingsAndRouteNewTasks(t *testing.T) {
+	cfg := testConfigWithTempVault(t)
+	cfg.Tasks.SectionHeadings = []string{"## Work"}
+	cfg.Tasks.SectionPattern = `^## Personal`
+	cfg.Tasks.TagSections = map[string]string{"#work": "## Work", "#Home": "## Personal life"}
+	today := localToday()
+	notePath := writeDailyNote(t, cfg, today, []string{
+		"- [ ] open space task",
+		"## Work",
+		"- [ ] work task",
+		"### Meetings",
+		"- [ ] nested under work",
+		"## Ignored",
+		"- [ ] ignored task",
+		"## Personal life",
+		"- [ ] personal task",
+		"# Archive",
+		"- [ ] archived task",
+	})
+
+	tasks, err := ScanDailyNotes(cfg)
+	if err != nil {
+		t.Fatalf("scan daily notes: %v", err)
+	}
+	var got []string
+	for _, task := range tasks {
+		got = append(got, task.Description+"@"+task.Section)
+	}
+	want := []string{
+		"open space task@## Open Space",
+		"work task@## Work",
+		"nested under work@## Work",
+		"personal task@## Personal life",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("unexpected tasks:\n got %q\nwant %q", got, want)
+	}
+
+	for _, task := range []Task{
+		{Description: "Deploy", Tags: []string{"#Work/ops"}, Priority: PriorityNone, DueDate: today},
+		{Description: "Water plants", Tags: []string{"#home"}, Priority: PriorityNone, DueDate: today},
+		{Description: "Untagged", Priority: PriorityNone, DueDate: today},
+	} {
+		if err := CreateTask(cfg, task); err != nil {
+			t.Fatalf("CreateTask(%s): %v", task.Description, err)
+		}
+	}
+	content, _ := os.ReadFile(notePath)
+	for _, want := range []string{
+		"## Open Space\n\n- [ ] Untagged",
+		"## Work\n- [ ] Deploy #Work/ops",
+		"## Personal life\n- [ ] Water plants #home",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Fatalf("expected %q in note:\n%s", want, content)
+		}
+	}
+
+	if _, err := CreateFollowUpTask(cfg, tasks[3]); err != nil {
+		t.Fatalf("CreateFollowUpTask: %v", err)
+	}
+	tomorrow := filepath.Join(cfg.Vault.Path, cfg.Vault.DailyNotesDir, today.AddDate(0, 0, 1).Format(cfg.Vault.DailyNoteFormat)+".md")
+	content, _ = os.ReadFile(tomorrow)
+	if !strings.Contains(string(content), "## Personal life\n\n- [ ] Follow up: personal task") {
+		t.Fatalf("expected follow-up in the original section:\n%s", content)
+	}
+}
+
+func TestTasksRoutedByTagAreReadBack(t *testing.T) {
+	cfg := testConfigWithTempVault(t)
+	cfg.Tasks.TagSections = map[string]string{"#errand": "## Errands"}
+	today := localToday()
+	writeDailyNote(t, cfg, today, []string{"- [ ] open space task"})
+
+	task := Task{Description: "Buy milk", Tags: []string{"#errand"}, Priority: PriorityNone, DueDate: today}
+	if err := CreateTask(cfg, task); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	tasks, err := ScanDailyNotes(cfg)
+	if err != nil {
+		t.Fatalf("scan daily notes: %v", err)
+	}
+	var got []string
+	for _, task := range tasks {
+		got = append(got, task.Description+"@"+task.Section)
+	}
+	want := []string{"open space task@## Open Space", "Buy milk@## Errands"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("unexpected tasks:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestNestedDailyNoteFormatsAreScannedCreatedAndWatched(t *testing.T) {
+	cfg := testConfigWithTempVault(t)
+	cfg.Vault.DailyNoteFormat = "2006/01-January/2006-01-02"
+	today := localToday()
+	writeDailyNote(t, cfg, today, []string{"- [ ] nested task"})
+
+	tasks, err := ScanDailyNotes(cfg)
+	if err != nil {
+		t.Fatalf("scan daily notes: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].Description != "nested task" {
+		t.Fatalf("expected the nested note's task, got %+v", tasks)
+	}
+
+	watcher, err := newDailyNotesWatcher(cfg)
+	if err != nil {
+		t.Fatalf("watch daily notes: %v", err)
+	}
+
+	// A day in a month with no folder yet: creating the task makes the
+	// folders, and the watcher has to pick them up.
+	later := today.AddDate(0, 2, 0)
+	if err := CreateTask(cfg, Task{Description: "later task", Priority: PriorityNone, DueDate: later}); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	notePath := filepath.Join(cfg.Vault.Path, cfg.Vault.DailyNotesDir, later.Format("2006"), later.Format("01-January"), later.Format("2006-01-02")+".md")
+	if content, err := os.ReadFile(notePath); err != nil || !strings.Contains(string(content), "- [ ] later task") {
+		t.Fatalf("expected task in %s: %v\n%s", notePath, err, content)
+	}
+
+	select {
+	case msg := <-watcher.events:
+		if msg.err != nil {
+			t.Fatalf("watcher error: %v", msg.err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a reload after the new folders were created")
+	}
+
+	scratch := filepath.Join(filepath.Dir(notePath), "scratch.md")
+	if err := os.WriteFile(scratch, []byte("- [ ] another\n"), 0o644); err != nil {
+		t.Fatalf("write note: %v", err)
+	}
+	select {
+	case <-watcher.events:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the new month folder to be watched")
+	}
+}
+
+func TestCreateTaskExpandsDailyNoteTemplate(t *testing.T) {
+	cfg := testConfigWithTempVault(t)
+	cfg.Vault.TemplatePath = "Templates/Daily"
+	templateFile := filepath.Join(cfg.Vault.Path, "Templates", "Daily.md")
+	if err := os.MkdirAll(filepath.Dir(templateFile), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	template := strings.Join([]string{
+		"---",
+		"created: {{date:YYYY-MM-DD}}",
+		"---",
+		"# {{title}} ({{date:dddd}})",
+		"[[{{yesterday}}]] · [[{{tomorrow}}]] · week ahead {{date+7d:MMM D}}",
+		"Templater: <% tp.file.title %> <% tp.date.now(\"YYYY-MM-DD\", 1, tp.file.title, \"YYYY-MM-DD\") %>",
+		"Untouched: <%* tR += 'x' %> <% tp.system.prompt() %> {{date:Do}}",
+		"",
+		"## Open Space",
+		"",
+		"- [ ] existing task",
+		"",
+	}, "\n")
+	if err := os.WriteFile(templateFile, []byte(template), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	day := time.Date(2026, 3, 14, 0, 0, 0, 0, time.Local)
+	if err := CreateTask(cfg, Task{Description: "new task", Priority: PriorityNone, DueDate: day}); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(cfg.Vault.Path, cfg.Vault.DailyNotesDir, "2026-03-14.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"---",
+		"created: 2026-03-14",
+		"---",
+		"# 2026-03-14 (Saturday)",
+		"[[2026-03-13]] · [[2026-03-15]] · week ahead Mar 21",
+		"Templater: 2026-03-14 2026-03-15",
+		"Untouched: <%* tR += 'x' %> <% tp.system.prompt() %> {{date:Do}}",
+		"",
+		"## Open Space",
+		"",
+		"- [ ] new task 📅 2026-03-14",
+		"- [ ] existing task",
+		"",
+	}, "\n")
+	if string(content) != want {
+		t.Fatalf("unexpected note:\n%s\nwant:\n%s", content, want)
+	}
+
+	cfg.Vault.TemplatePath = "Templates/Missing"
+	if err := CreateTask(cfg, Task{Description: "x", Priority: PriorityNone, DueDate: day.AddDate(0, 0, 1)}); err == nil {
+		t.Fatal("expected a missing template to be reported")
+	}
+
+	// In nested folders the title is the file name, without its folders.
+	if got := expandTemplate("{{title}} <% tp.file.title %>", day, "2006/01/2006-01-02", day); got != "2026-03-14 2026-03-14" {
+		t.Fatalf("unexpected nested title: %q", got)
+	}
+}
+
+func TestTaskContextFindsChildrenInsideCallouts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "note.md")
+	lines := []string{
+		"> [!todo] Launch",
+		"> - [ ] Parent",
+		">   - [ ] Child",
+		">     more about the child",
+		"> - [ ] Sibling",
+		"",
+		"- [ ] Plain parent",
+		"  - [ ] Plain child",
+		"> quoted, not a child",
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		line int
+		want []string
+	}{
+		{2, []string{">   - [ ] Child", ">     more about the child"}},
+		{3, []string{">     more about the child"}},
+		{5, nil},
+		{7, []string{"  - [ ] Plain child"}},
+	} {
+		task := Task{FilePath: path, LineNumber: tc.line, RawLine: lines[tc.line-1]}
+		got, err := TaskContext(task, 0)
+		if err != nil {
+			t.Fatalf("context of %q: %v", task.RawLine, err)
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Fatalf("context of %q = %q, want %q", task.RawLine, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// notePeriod is the week or month covered by a periodic note.
+type notePeriod struct {
+	Kind  string // "week" or "month"
+	Start time.Time
+	End   time.Time // the last day
+}
+
+func (p notePeriod) contains(day time.Time) bool {
+	return !day.Before(p.Start) && !day.After(p.End)
+}
+
+// label names the period for display, e.g. "Week of Oct 12".
+func (p notePeriod) label() string {
+	if p.Kind == "month" {
+		return p.Start.Format("January 2006")
+	}
+	return "Week of " + p.Start.Format("Jan 02")
+}
+
+// periodicSource is a kind of periodic note that tasks are read from.
+type periodicSource struct {
+	name      string // the config key prefix, "weekly" or "monthly"
+	kind      string
+	dir       string
+	format    string
+	weekStart time.Weekday
+}
+
+// periodicSources lists the weekly and monthly notes configured as task
+// sources.
+func (cfg Config) periodicSources() []periodicSource {
+	weekStart := newDateParser(cfg).WeekStart
+	var sources []periodicSource
+	if cfg.Vault.WeeklyNoteFormat != "" {
+		sources = append(sources, periodicSource{"weekly", "week", cfg.Vault.WeeklyNotesDir, cfg.Vault.WeeklyNoteFormat, weekStart})
+	}
+	if cfg.Vault.MonthlyNoteFormat != "" {
+		sources = append(sources, periodicSource{"monthly", "month", cfg.Vault.MonthlyNotesDir, cfg.Vault.MonthlyNoteFormat, weekStart})
+	}
+	return sources
+}
+
+// periodAt returns the week or month holding day.
+func (s periodicSource) periodAt(day time.Time) notePeriod {
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	if s.kind == "month" {
+		start := day.AddDate(0, 0, 1-day.Day())
+		return notePeriod{Kind: s.kind, Start: start, End: start.AddDate(0, 1, -1)}
+	}
+	start := day.AddDate(0, 0, -((int(day.Weekday()) - int(s.weekStart) + 7) % 7))
+	return notePeriod{Kind: s.kind, Start: start, End: start.AddDate(0, 0, 6)}
+}
+
+// notePath returns the note for period p.
+func (s periodicSource) notePath(cfg Config, p notePeriod) (string, error) {
+	name, err := formatMoment(p.Start, s.format, s.weekStart)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cfg.Vault.Path, s.dir, filepath.FromSlash(name)+".md"), nil
+}
+
+// scanPeriodicNotes reads the tasks of the notes whose period overlaps start
+// through end. Tasks without their own 📅 date fall due at the start of
+// their note's period and carry it in Period.
+func scanPeriodicNotes(cfg Config, s periodicSource, start, end time.Time, sections sectionMatcher) []Task {
+	var tasks []Task
+	for p := s.periodAt(start); !p.Start.After(end); p = s.periodAt(p.End.AddDate(0, 0, 1)) {
+		fp, err := s.notePath(cfg, p)
+		if err != nil {
+			return tasks
+		}
+		if _, err := os.Stat(fp); err != nil {
+			continue
+		}
+		parsed, err := parseFileSections(fp, p.Start, sections)
+		if err != nil {
+			continue
+		}
+		for _, t := range parsed {
+			if !t.HasDueDate {
+				t.Period = p
+			}
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks
+}
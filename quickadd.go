@@ -0,0 +1,206 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxDatePhraseWords bounds how many words quick-add tries to read as a single
+// date expression, e.g. "last day of march".
+const maxDatePhraseWords = 4
+
+// recurrenceWords are the words that may follow "every" in a recurrence rule.
+var recurrenceWords = map[string]bool{
+	"day": true, "days": true, "week": true, "weeks": true,
+	"month": true, "months": true, "year": true, "years": true,
+	"weekday": true, "weekdays": true, "other": true, "on": true, "the": true,
+	"and": true, "when": true, "done": true, "last": true,
+	"monday": true, "tuesday": true, "wednesday": true, "thursday": true,
+	"friday": true, "saturday": true, "sunday": true,
+	"mon": true, "tue": true, "wed": true, "thu": true, "fri": true, "sat": true, "sun": true,
+}
+
+// parseQuickAdd reads a quick-add line into a task. It understands inline
+// phrases such as `due fri`, `^tomorrow`, `@scheduled +2w`, `@start mon`,
+// `every monday`, `!`/`!!`/`!!!` and `p1`..`p5` priorities, `#tags` and the
+// literal Obsidian Tasks date emojis. Tasks without a due date fall back to
+// defaultDue.
+func parseQuickAdd(input string, defaultDue time.Time, parseDate func(string) (time.Time, error)) Task {
+	task := Task{Priority: PriorityNone, Status: StatusTodo}
+
+	task.DueDate = parseDateToken(dueDateRe, input)
+	task.ScheduledDate = parseDateToken(scheduledDateRe, input)
+	task.StartDate = parseDateToken(startDateRe, input)
+	input = dueDateRe.ReplaceAllString(input, "")
+	input = scheduledDateRe.ReplaceAllString(input, "")
+	input = startDateRe.ReplaceAllString(input, "")
+
+	tokens := strings.Fields(input)
+	var desc []string
+
+	// readDate consumes the longest run of tokens starting at i that parses
+	// as a date, returning the date and the index after it.
+	readDate := func(i int, first string) (time.Time, int, bool) {
+		for n := min(maxDatePhraseWords, len(tokens)-i); n >= 1; n-- {
+			words := append([]string{first}, tokens[i+1:i+n]...)
+			if d, err := parseDate(strings.Join(words, " ")); err == nil {
+				return d, i + n, true
+			}
+		}
+		return time.Time{}, i, false
+	}
+
+	for i := 0; i < len(tokens); {
+		tok := tokens[i]
+		lower := strings.ToLower(tok)
+
+		switch {
+		case isValidTag(tok):
+			task.Tags = append(task.Tags, tok)
+			i++
+			continue
+
+		case strings.Trim(tok, "!") == "":
+			switch len(tok) {
+			case 1:
+				task.Priority = PriorityMedium
+			case 2:
+				task.Priority = PriorityHigh
+			default:
+				task.Priority = PriorityHighest
+			}
+			i++
+			continue
+
+		case len(lower) == 2 && lower[0] == 'p' && lower[1] >= '1' && lower[1] <= '5':
+			task.Priority, _ = parsePriority(lower)
+			i++
+			continue
+
+		case isPriorityEmoji(tok):
+			task.Priority = emojiToPriority[tok]
+			i++
+			continue
+
+		case strings.HasPrefix(tok, "^") && len(tok) > 1:
+			if d, next, ok := readDate(i, tok[1:]); ok {
+				task.DueDate = d
+				i = next
+				continue
+			}
+
+		case lower == "due" || lower == "@due" || lower == "@scheduled" || lower == "@sched" || lower == "@start":
+			if i+1 < len(tokens) {
+				if d, next, ok := readDate(i+1, tokens[i+1]); ok {
+					switch lower {
+					case "@scheduled", "@sched":
+						task.ScheduledDate = d
+					case "@start":
+						task.StartDate = d
+					default:
+						task.DueDate = d
+					}
+					i = next
+					continue
+				}
+			}
+
+		case lower == "every" && i+1 < len(tokens):
+			j := i + 1
+			for j < len(tokens) && isRecurrenceWord(tokens[j]) {
+				j++
+			}
+			if j > i+1 {
+				task.Recurrence = strings.ToLower(strings.Join(tokens[i:j], " "))
+				i = j
+				continue
+			}
+		}
+
+		desc = append(desc, tok)
+		i++
+	}
+
+	task.Description = strings.Join(desc, " ")
+	if task.DueDate.IsZero() {
+		task.DueDate = defaultDue
+	}
+	task.HasDueDate = !task.DueDate.IsZero()
+	return task
+}
+
+func isPriorityEmoji(tok string) bool {
+	_, ok := emojiToPriority[tok]
+	return ok
+}
+
+func isRecurrenceWord(tok string) bool {
+	tok = strings.ToLower(strings.TrimRight(tok, ","))
+	if recurrenceWords[tok] {
+		return true
+	}
+	for _, r := range tok {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return tok != ""
+}
+
+// newTaskDefaultDate is the due date for quick-add tasks that don't name
+// one: the date under the cursor in date-based views, otherwise today, or no
+// date at all when there is an inbox to put the task in.
+func (m Model) newTaskDefaultDate() time.Time {
+	dueDate := localToday()
+	if m.cfg.Vault.InboxFile != "" {
+		dueDate = time.Time{}
+	}
+	switch m.activeView {
+	case viewUpcoming:
+		if len(m.upcomingGroups) > 0 {
+			groupIdx := m.groupIndexForCursor()
+			if groupIdx >= 0 && groupIdx < len(m.upcomingGroups) {
+				dueDate = m.upcomingGroups[groupIdx].Date
+			}
+		}
+	case viewCalendar:
+		dueDate = m.calendarDay()
+	case viewWeek:
+		if cols := m.weekColumns(); m.boardCol < len(cols) {
+			dueDate = cols[m.boardCol].Date
+		}
+	}
+	return dueDate
+}
+
+func (m Model) renderQuickAddPreview() string {
+	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.cfg.Theme.Muted))
+	var lines []string
+
+	if line := m.renderTagSuggestions(m.input); line != "" {
+		lines = append(lines, line)
+	}
+
+	if m.mode == modeNewTask && strings.TrimSpace(m.input.Value()) != "" {
+		task := parseQuickAdd(m.input.Value(), m.newTaskDefaultDate(), m.parseDate)
+		target := dailyNoteName(m.cfg, task.DueDate)
+		if task.IsUndated() {
+			target = m.cfg.Vault.InboxFile
+		}
+		if section := m.cfg.Tasks.taskSection(task); section != "" {
+			target += " › " + section
+		}
+		lines = append(lines, mutedStyle.Render("   → ")+formatTaskLine(task)+mutedStyle.Render("  ("+target+")"))
+	}
+	if warning := m.newTagWarning(m.typedTags(m.input)); warning != "" {
+		warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.cfg.Theme.Overdue))
+		lines = append(lines, warnStyle.Render("   ⚠ "+warning))
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+	return "\n" + strings.Join(lines, "\n")
+}
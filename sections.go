@@ -0,0 +1,96 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// sectionMatcher decides which headings start a section whose tasks are
+// read. With no headings and no pattern, every task in a note is read.
+type sectionMatcher struct {
+	headings []string
+	pattern  *regexp.Regexp
+}
+
+// newSectionMatcher builds the matcher for section_heading,
+// section_headings and section_pattern. When reading is limited to some
+// sections, the tag_sections headings are read too, so tasks routed there
+// don't drop out of view.
+func newSectionMatcher(tc TasksConfig) (sectionMatcher, error) {
+	var s sectionMatcher
+	for _, h := range append([]string{tc.SectionHeading}, tc.SectionHeadings...) {
+		if h = strings.TrimSpace(h); h != "" {
+			s.headings = append(s.headings, h)
+		}
+	}
+	if tc.SectionPattern != "" {
+		re, err := regexp.Compile(tc.SectionPattern)
+		if err != nil {
+			return s, err
+		}
+		s.pattern = re
+	}
+	if !s.all() {
+		var routed []string
+		for _, h := range tc.TagSections {
+			if h = strings.TrimSpace(h); h != "" {
+				routed = append(routed, h)
+			}
+		}
+		sort.Strings(routed)
+		s.headings = append(s.headings, routed...)
+	}
+	return s, nil
+}
+
+func (s sectionMatcher) all() bool {
+	return len(s.headings) == 0 && s.pattern == nil
+}
+
+// matches reports whether line, trimmed, opens a section to read.
+func (s sectionMatcher) matches(line string) bool {
+	for _, h := range s.headings {
+		if line == h {
+			return true
+		}
+	}
+	return s.pattern != nil && headingRe.MatchString(line) && s.pattern.MatchString(line)
+}
+
+// headingLevel returns the number of #s opening a markdown heading, or 0.
+func headingLevel(line string) int {
+	if !headingRe.MatchString(line) {
+		return 0
+	}
+	return len(line) - len(strings.TrimLeft(line, "#"))
+}
+
+// sectionFor picks the heading a new task with tags is written under: the
+// tag_sections entry for its most specific mapped tag, else section_heading.
+func (tc TasksConfig) sectionFor(tags []string) string {
+	best, section := "", ""
+	for _, tag := range tags {
+		for mapped, heading := range tc.TagSections {
+			if tagWithin(tag, mapped) && len(mapped) > len(best) {
+				best, section = mapped, heading
+			}
+		}
+	}
+	if section != "" {
+		return section
+	}
+	if tc.SectionHeading == "" && len(tc.SectionHeadings) > 0 {
+		return tc.SectionHeadings[0]
+	}
+	return tc.SectionHeading
+}
+
+// taskSection is where a new task goes: its own section when it has one,
+// otherwise the one its tags map to.
+func (tc TasksConfig) taskSection(task Task) string {
+	if task.Section != "" {
+		return task.Section
+	}
+	return tc.sectionFor(task.Tags)
+}
@@ -0,0 +1,518 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// vaultTagsMsg carries the tag counts found by scanning every note in the
+// vault.
+type vaultTagsMsg struct {
+	tags map[string]int
+	err  error
+}
+
+// renamePlanMsg carries the lines a vault-wide tag rename would change.
+type renamePlanMsg struct {
+	from, to string
+	changes  []tagRenameChange
+	err      error
+}
+
+// walkVaultNotes calls fn with the path of every markdown note in the vault,
+// skipping hidden directories such as .obsidian and .trash.
+func walkVaultNotes(vaultPath string, fn func(path string) error) error {
+	return filepath.WalkDir(vaultPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != vaultPath && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(strings.ToLower(d.Name()), ".md") {
+			return nil
+		}
+		return fn(path)
+	})
+}
+
+// ScanVaultTags counts every tag used in the vault's markdown files. Code,
+// comments and frontmatter are skipped, as in PlanTagRename.
+func ScanVaultTags(vaultPath string) (map[string]int, error) {
+	counts := make(map[string]int)
+	err := walkVaultNotes(vaultPath, func(path string) error {
+		lines, err := readLines(path)
+		if err != nil {
+			return nil
+		}
+		var classifier lineClassifier
+		for _, line := range lines {
+			if !classifier.isContent(line) {
+				continue
+			}
+			for _, tag := range extractTags(line) {
+				counts[tag]++
+			}
+		}
+		return nil
+	})
+	return counts, err
+}
+
+// tagRenameChange is one line a vault-wide tag rename will rewrite.
+type tagRenameChange struct {
+	Path       string
+	LineNumber int
+	Before     string
+	After      string
+}
+
+// renameTag maps tag to its new name when it is from or nested under it,
+// matching case-insensitively so #Work and #work are renamed together.
+func renameTag(tag, from, to string) string {
+	n := foldPrefixLen(tag, from)
+	switch {
+	case n == len(tag):
+		return to
+	case n >= 0 && tag[n] == '/':
+		return to + tag[n:]
+	}
+	return tag
+}
+
+// PlanTagRename finds every line in the vault that renaming from to to would
+// change, nested tags included. Code, comments and frontmatter are left
+// alone.
+func PlanTagRename(vaultPath, from, to string) ([]tagRenameChange, error) {
+	var changes []tagRenameChange
+	err := walkVaultNotes(vaultPath, func(path string) error {
+		lines, err := readLines(path)
+		if err != nil {
+			return err
+		}
+		var classifier lineClassifier
+		for i, line := range lines {
+			if !classifier.isContent(line) {
+				continue
+			}
+			after := editTagsInLine(line, func(tag string) string { return renameTag(tag, from, to) })
+			if after != line {
+				changes = append(changes, tagRenameChange{Path: path, LineNumber: i + 1, Before: line, After: after})
+			}
+		}
+		return nil
+	})
+	return changes, err
+}
+
+// ApplyTagRename writes a planned rename. Every note is checked against the
+// preview first; if any planned line has changed since, nothing is written.
+func ApplyTagRename(changes []tagRenameChange) (int, error) {
+	byPath := make(map[string][]tagRenameChange)
+	var paths []string
+	for _, c := range changes {
+		if _, ok := byPath[c.Path]; !ok {
+			paths = append(paths, c.Path)
+		}
+		byPath[c.Path] = append(byPath[c.Path], c)
+	}
+
+	renamed := make([][]string, len(paths))
+	for i, path := range paths {
+		lines, err := readLines(path)
+		if err != nil {
+			return 0, err
+		}
+		for _, c := range byPath[path] {
+			if err := verifyLine(lines, c.LineNumber-1, c.Before); err != nil {
+				return 0, fmt.Errorf("%s: %w", filepath.Base(path), err)
+			}
+			lines[c.LineNumber-1] = c.After
+		}
+		renamed[i] = lines
+	}
+
+	for i, path := range paths {
+		if err := writeLines(path, renamed[i]); err != nil {
+			return i, err
+		}
+	}
+	return len(paths), nil
+}
+
+func (m Model) loadVaultTagsCmd() tea.Cmd {
+	if !m.cfg.Tasks.WarnNewTags || m.cfg.Vault.Path == "" {
+		return nil
+	}
+	vaultPath := m.cfg.Vault.Path
+	return func() tea.Msg {
+		tags, err := ScanVaultTags(vaultPath)
+		return vaultTagsMsg{tags: tags, err: err}
+	}
+}
+
+// tagScores ranks every known tag by how often it's used, boosted by how
+// recently a task carrying it was due or closed.
+func (m Model) tagScores() map[string]float64 {
+	today := localToday()
+	scores := make(map[string]float64)
+	for tag, count := range m.vaultTags {
+		scores[tag] += float64(count)
+	}
+	lastUsed := make(map[string]float64)
+	for _, t := range m.allTasks {
+		when := t.DueDate
+		if closed := t.ClosedDate(); !closed.IsZero() {
+			when = closed
+		}
+		daysAgo := math.Max(0, today.Sub(dueDateAtLocation(when, today.Location())).Hours()/24)
+		recency := 5 / (1 + daysAgo)
+		for _, tag := range t.Tags {
+			if m.vaultTags == nil {
+				scores[tag]++
+			} else if _, ok := scores[tag]; !ok {
+				scores[tag] = 1
+			}
+			lastUsed[tag] = math.Max(lastUsed[tag], recency)
+		}
+	}
+	for tag, recency := range lastUsed {
+		scores[tag] += recency
+	}
+	return scores
+}
+
+// knownTags returns every tag in use, best ranked first.
+func (m Model) knownTags() []string {
+	scores := m.tagScores()
+	tags := make([]string, 0, len(scores))
+	for tag := range scores {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if scores[tags[i]] != scores[tags[j]] {
+			return scores[tags[i]] > scores[tags[j]]
+		}
+		return tags[i] < tags[j]
+	})
+	return tags
+}
+
+// tagCompletions returns known tags matching prefix, ranked. Tags whose
+// nested segment matches (`#ops` → `#kanastra/ops`) come after direct
+// prefix matches.
+func (m Model) tagCompletions(prefix string) []string {
+	if prefix == "" {
+		return nil
+	}
+	lower := strings.ToLower(prefix)
+	segment := "/" + strings.TrimPrefix(lower, "#")
+	var direct, nested []string
+	for _, tag := range m.knownTags() {
+		if tag == prefix {
+			continue
+		}
+		low := strings.ToLower(tag)
+		if strings.HasPrefix(low, lower) {
+			direct = append(direct, tag)
+		} else if len(segment) > 1 && strings.Contains(low, segment) {
+			nested = append(nested, tag)
+		}
+	}
+	return append(direct, nested...)
+}
+
+// tagPrefixOf returns the `#tag` fragment right before the cursor of ti.
+func tagPrefixOf(ti textinput.Model) string {
+	value := string([]rune(ti.Value())[:ti.Position()])
+	word := value[strings.LastIndexAny(value, " \t,")+1:]
+	if strings.HasPrefix(word, "#") {
+		return word
+	}
+	return ""
+}
+
+func (m Model) currentTagPrefix() string {
+	return tagPrefixOf(m.input)
+}
+
+// completeTag replaces the tag being typed in ti with the next completion,
+// cycling through candidates on repeated presses.
+func (m *Model) completeTag(ti *textinput.Model) bool {
+	if len(m.tagCycle) == 0 {
+		m.tagCyclePrefix = tagPrefixOf(*ti)
+		m.tagCycle = m.tagCompletions(m.tagCyclePrefix)
+		m.tagCycleIdx = 0
+		if len(m.tagCycle) == 0 {
+			return false
+		}
+	} else {
+		m.tagCycleIdx = (m.tagCycleIdx + 1) % len(m.tagCycle)
+	}
+
+	value := []rune(ti.Value())
+	pos := ti.Position()
+	start := pos
+	for start > 0 && !strings.ContainsRune(" \t,", value[start-1]) {
+		start--
+	}
+	completion := []rune(m.tagCycle[m.tagCycleIdx])
+	ti.SetValue(string(value[:start]) + string(completion) + string(value[pos:]))
+	ti.SetCursor(start + len(completion))
+	return true
+}
+
+// typedTags returns the complete tags in ti, leaving out the one still
+// being typed at the cursor.
+func (m Model) typedTags(ti textinput.Model) []string {
+	typing := tagPrefixOf(ti)
+	if len(m.tagCycle) > 0 {
+		typing = ""
+	}
+	var tags []string
+	for _, tag := range extractTags(ti.Value()) {
+		if tag != typing {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// newTagWarning describes tags that don't exist anywhere in the vault, when
+// warn_new_tags is enabled, pointing at the most used differently-cased
+// match if one exists.
+func (m Model) newTagWarning(tags []string) string {
+	if !m.cfg.Tasks.WarnNewTags || len(tags) == 0 {
+		return ""
+	}
+	scores := m.tagScores()
+	var notes []string
+	for _, tag := range tags {
+		if _, ok := scores[tag]; ok {
+			continue
+		}
+		note := "new tag " + tag
+		best := ""
+		for known, score := range scores {
+			if !strings.EqualFold(known, tag) {
+				continue
+			}
+			if best == "" || score > scores[best] || score == scores[best] && known < best {
+				best = known
+			}
+		}
+		if best != "" {
+			note += " (did you mean " + best + "?)"
+		}
+		notes = append(notes, note)
+	}
+	return strings.Join(notes, ", ")
+}
+
+func (m Model) renderTagSuggestions(ti textinput.Model) string {
+	prefix := tagPrefixOf(ti)
+	if len(m.tagCycle) > 0 {
+		prefix = m.tagCyclePrefix
+	}
+	completions := m.tagCompletions(prefix)
+	if len(completions) == 0 {
+		return ""
+	}
+
+	var parts []string
+	for i, tag := range completions {
+		if i == 5 {
+			break
+		}
+		style := lipgloss.NewStyle().Foreground(tagColor(tag))
+		if len(m.tagCycle) > 0 && tag == m.tagCycle[m.tagCycleIdx] {
+			style = style.Bold(true).Underline(true)
+		}
+		parts = append(parts, style.Render(tag))
+	}
+	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.cfg.Theme.Muted))
+	return mutedStyle.Render("   tab ") + strings.Join(parts, " ")
+}
+
+func (m Model) openTagInput(mode int) (tea.Model, tea.Cmd) {
+	if len(m.targets()) == 0 {
+		return m, nil
+	}
+	m.mode = mode
+	m.input.Placeholder = "#work #ops"
+	m.input.SetValue("")
+	m.input.Focus()
+	return m, m.input.Cursor.BlinkCmd()
+}
+
+// editTargetTags adds and removes tags on every target task.
+func (m Model) editTargetTags(add, remove []string) Model {
+	indices := m.targets()
+	if len(indices) == 0 || len(add)+len(remove) == 0 {
+		return m
+	}
+
+	changed := 0
+	for _, idx := range indices {
+		ok, err := EditTaskTags(&m.allTasks[idx], add, remove)
+		if err != nil {
+			m.err = err
+			m.statusMsg = "Error: " + err.Error()
+			m.statusTime = time.Now()
+			return m.reload()
+		}
+		if ok {
+			changed++
+		}
+	}
+
+	var ops []string
+	for _, tag := range add {
+		ops = append(ops, "+"+tag)
+	}
+	for _, tag := range remove {
+		ops = append(ops, "-"+tag)
+	}
+	m.selected = make(map[int]bool)
+	m.markInternalWrite(fmt.Sprintf("%s on %d of %d tasks", strings.Join(ops, " "), changed, len(indices)))
+	return m.reload()
+}
+
+// parseTagOps reads "+#work -#old #ops" into tags to add and remove; tags
+// without a sign are added.
+func parseTagOps(input string) (add, remove []string) {
+	for _, field := range strings.Fields(input) {
+		switch {
+		case strings.HasPrefix(field, "-"):
+			remove = append(remove, parseTagList(field[1:])...)
+		case strings.HasPrefix(field, "+"):
+			add = append(add, parseTagList(field[1:])...)
+		default:
+			add = append(add, parseTagList(field)...)
+		}
+	}
+	return add, remove
+}
+
+// planRename scans the vault for the lines renaming from to to would change,
+// in the background.
+func (m Model) planRename(from, to string) (tea.Model, tea.Cmd) {
+	fromTags, toTags := parseTagList(from), parseTagList(to)
+	if len(fromTags) != 1 || len(toTags) != 1 || !isValidTag(toTags[0]) {
+		m.statusMsg = "Usage: rename_tag #old #new"
+		m.statusTime = time.Now()
+		return m, nil
+	}
+	m.statusMsg = "Looking for " + fromTags[0] + " across the vault…"
+	m.statusTime = time.Now()
+	vaultPath, from, to := m.cfg.Vault.Path, fromTags[0], toTags[0]
+	return m, func() tea.Msg {
+		changes, err := PlanTagRename(vaultPath, from, to)
+		return renamePlanMsg{from: from, to: to, changes: changes, err: err}
+	}
+}
+
+// showRenamePlan opens the preview for a finished rename scan, unless
+// another prompt was opened meanwhile.
+func (m Model) showRenamePlan(msg renamePlanMsg) Model {
+	if m.mode != modeNormal {
+		return m
+	}
+	if msg.err != nil {
+		m.statusMsg = "Error: " + msg.err.Error()
+		m.statusTime = time.Now()
+		return m
+	}
+	if len(msg.changes) == 0 {
+		m.statusMsg = "No notes use " + msg.from
+		m.statusTime = time.Now()
+		return m
+	}
+	m.statusMsg = ""
+	m.renameFrom, m.renameTo = msg.from, msg.to
+	m.renamePlan = msg.changes
+	m.mode = modeRenamePreview
+	return m
+}
+
+func (m Model) handleRenamePreview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	km := m.keymap()
+	switch {
+	case km.is("confirm_yes", msg) || msg.String() == "enter":
+		m.mode = modeNormal
+		files, err := ApplyTagRename(m.renamePlan)
+		count := len(m.renamePlan)
+		m.renamePlan = nil
+		if err != nil {
+			m.err = err
+			m.statusMsg = "Error: " + err.Error()
+			m.statusTime = time.Now()
+			return m.reload(), nil
+		}
+		m.markInternalWrite(fmt.Sprintf("%s → %s on %d lines in %d notes", m.renameFrom, m.renameTo, count, files))
+		m = m.reload()
+		if m.vaultTags != nil {
+			return m, m.loadVaultTagsCmd()
+		}
+		return m, nil
+	case km.is("confirm_no", msg):
+		m.mode = modeNormal
+		m.renamePlan = nil
+	}
+	return m, nil
+}
+
+func (m Model) renderRenamePreview() string {
+	accent := lipgloss.Color(m.cfg.Theme.Accent)
+	muted := lipgloss.NewStyle().Foreground(lipgloss.Color(m.cfg.Theme.Muted))
+	titleStyle := lipgloss.NewStyle().Foreground(accent).Bold(true)
+
+	notes := make(map[string]bool)
+	for _, c := range m.renamePlan {
+		notes[c.Path] = true
+	}
+	rows := []string{
+		titleStyle.Render(fmt.Sprintf("Rename %s → %s", m.renameFrom, m.renameTo)),
+		muted.Render(fmt.Sprintf("%d lines in %d notes", len(m.renamePlan), len(notes))),
+		"",
+	}
+
+	const shown = 12
+	for i, c := range m.renamePlan {
+		if i == shown {
+			rows = append(rows, muted.Render(fmt.Sprintf("… and %d more", len(m.renamePlan)-shown)))
+			break
+		}
+		rel, err := filepath.Rel(m.cfg.Vault.Path, c.Path)
+		if err != nil {
+			rel = c.Path
+		}
+		rows = append(rows, muted.Render(fmt.Sprintf("%s:%d", rel, c.LineNumber)))
+		rows = append(rows, "  "+truncateText(strings.TrimSpace(c.After), 66))
+	}
+
+	km := m.keymap()
+	rows = append(rows, "", muted.Render(fmt.Sprintf("%s apply  %s cancel", km.label("confirm_yes"), km.label("confirm_no"))))
+
+	style := lipgloss.NewStyle().
+		Border(subtleBorder).
+		BorderForeground(accent).
+		Padding(1, 3).
+		Width(76)
+
+	return lipgloss.Place(m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		style.Render(strings.Join(rows, "\n")))
+}
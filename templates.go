@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// coreTemplateRe matches the tags Obsidian's daily notes plugin expands:
+	// {{date}}, {{time}}, {{title}}, {{yesterday}}, {{tomorrow}}, and
+	// {{date+1d:FORMAT}} style offsets and formats.
+	coreTemplateRe = regexp.MustCompile(`(?i){{\s*(date|time|title|yesterday|tomorrow)\s*(?:([+-]\d+)([yqmwdhs]))?\s*(:[^}]*)?}}`)
+	// templaterRe matches a Templater <% … %> tag; <%* … %> scripts are
+	// left alone.
+	templaterRe = regexp.MustCompile(`<%[-_]?\s*([^*%][^%]*?)\s*[-_]?%>`)
+	// templaterDateRe matches the tp.date functions we can evaluate.
+	templaterDateRe = regexp.MustCompile(`^tp\.date\.(now|today|tomorrow|yesterday)\((.*)\)$`)
+)
+
+// dailyNoteTemplate reads the vault's daily note template and expands it for
+// the note of day.
+func dailyNoteTemplate(cfg Config, day time.Time) ([]string, error) {
+	data, err := os.ReadFile(vaultNotePath(cfg, cfg.Vault.TemplatePath))
+	if err != nil {
+		return nil, fmt.Errorf("template_path: %w", err)
+	}
+	content := strings.ReplaceAll(string(data), "\r\n", "\n")
+	content = expandTemplate(content, day, cfg.Vault.DailyNoteFormat, time.Now())
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n"), nil
+}
+
+// expandTemplate fills in the date tags of a daily note template for the
+// note of day, named with layout. Tags it can't evaluate are kept as written.
+func expandTemplate(content string, day time.Time, layout string, now time.Time) string {
+	name := day.Format(layout)
+	content = coreTemplateRe.ReplaceAllStringFunc(content, func(tag string) string {
+		m := coreTemplateRe.FindStringSubmatch(tag)
+		kind, delta, unit, format := strings.ToLower(m[1]), m[2], m[3], strings.TrimSpace(strings.TrimPrefix(m[4], ":"))
+		if delta == "" && format == "" {
+			switch kind {
+			case "date":
+				return name
+			case "title":
+				return filepath.Base(name)
+			case "time":
+				return now.Format("15:04")
+			}
+		}
+		switch kind {
+		case "title":
+			return tag
+		case "yesterday":
+			return day.AddDate(0, 0, -1).Format(layout)
+		case "tomorrow":
+			return day.AddDate(0, 0, 1).Format(layout)
+		}
+
+		// The note's day at the current time of day, like the plugin.
+		at := time.Date(day.Year(), day.Month(), day.Day(), now.Hour(), now.Minute(), now.Second(), 0, day.Location())
+		if delta != "" {
+			n, _ := strconv.Atoi(delta)
+			at = addMomentUnit(at, n, unit)
+		}
+		if format == "" {
+			return at.Format(layout)
+		}
+		goLayout, err := momentToGoLayout(format)
+		if err != nil {
+			return tag
+		}
+		return at.Format(goLayout)
+	})
+
+	return templaterRe.ReplaceAllStringFunc(content, func(tag string) string {
+		expr := templaterRe.FindStringSubmatch(tag)[1]
+		if expr == "tp.file.title" {
+			return filepath.Base(name)
+		}
+		if value, ok := templaterDate(expr, day, now); ok {
+			return value
+		}
+		return tag
+	})
+}
+
+// addMomentUnit adds n of a moment.js duration unit. As in moment, m is
+// minutes and M months.
+func addMomentUnit(t time.Time, n int, unit string) time.Time {
+	switch unit {
+	case "y", "Y":
+		return t.AddDate(n, 0, 0)
+	case "q", "Q":
+		return t.AddDate(0, 3*n, 0)
+	case "M":
+		return t.AddDate(0, n, 0)
+	case "w", "W":
+		return t.AddDate(0, 0, 7*n)
+	case "d", "D":
+		return t.AddDate(0, 0, n)
+	case "h", "H":
+		return t.Add(time.Duration(n) * time.Hour)
+	case "m":
+		return t.Add(time.Duration(n) * time.Minute)
+	default:
+		return t.Add(time.Duration(n) * time.Second)
+	}
+}
+
+// templaterDate evaluates tp.date.now(format, offset, reference,
+// reference_format) and tp.date.today, tomorrow and yesterday(format). The
+// offset is in days, and a tp.file.title reference stands for the note's
+// day.
+func templaterDate(expr string, day, now time.Time) (string, bool) {
+	m := templaterDateRe.FindStringSubmatch(expr)
+	if m == nil {
+		return "", false
+	}
+	args, ok := templaterArgs(m[2])
+	if !ok || len(args) > 4 || m[1] != "now" && len(args) > 1 {
+		return "", false
+	}
+
+	format := "YYYY-MM-DD"
+	if len(args) > 0 && args[0] != "" {
+		format = args[0]
+	}
+	layout, err := momentToGoLayout(format)
+	if err != nil {
+		return "", false
+	}
+
+	at := now
+	switch m[1] {
+	case "tomorrow":
+		at = at.AddDate(0, 0, 1)
+	case "yesterday":
+		at = at.AddDate(0, 0, -1)
+	}
+	if len(args) > 2 {
+		if args[2] != "tp.file.title" {
+			return "", false
+		}
+		at = day
+	}
+	if len(args) > 1 && args[1] != "" {
+		days, err := strconv.Atoi(args[1])
+		if err != nil {
+			return "", false
+		}
+		at = at.AddDate(0, 0, days)
+	}
+	return at.Format(layout), true
+}
+
+// templaterArgs splits a call's arguments, unquoting strings. Anything other
+// than strings, integers and tp.file.title is not understood.
+func templaterArgs(s string) ([]string, bool) {
+	var args []string
+	for s = strings.TrimSpace(s); s != ""; {
+		var arg string
+		switch s[0] {
+		case '"', '\'', '`':
+			end := strings.IndexByte(s[1:], s[0])
+			if end < 0 {
+				return nil, false
+			}
+			arg, s = s[1:end+1], s[end+2:]
+		default:
+			end := strings.IndexByte(s, ',')
+			if end < 0 {
+				end = len(s)
+			}
+			arg, s = strings.TrimSpace(s[:end]), s[end:]
+			if _, err := strconv.Atoi(arg); err != nil && arg != "tp.file.title" {
+				return nil, false
+			}
+		}
+		args = append(args, arg)
+		s = strings.TrimSpace(s)
+		if s == "" {
+			break
+		}
+		if s[0] != ',' {
+			return nil, false
+		}
+		s = strings.TrimSpace(s[1:])
+	}
+	return args, true
+}
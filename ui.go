@@ -3,12 +3,15 @@ package main
 import (
 	"fmt"
 	"hash/fnv"
+	"maps"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 const hPad = 2
@@ -17,8 +20,28 @@ const (
 	viewToday = iota
 	viewUpcoming
 	viewLogbook
+	viewCalendar
+	viewWeek
+	viewBoard
+	viewInbox
 )
 
+type sidebarItem struct {
+	icon  string
+	label string
+	view  int
+}
+
+var sidebarItems = []sidebarItem{
+	{"☀️", "Today", viewToday},
+	{"📅", "Upcoming", viewUpcoming},
+	{"📓", "Logbook", viewLogbook},
+	{"📆", "Calendar", viewCalendar},
+	{"📋", "Week", viewWeek},
+	{"📌", "Board", viewBoard},
+	{"📥", "Inbox", viewInbox},
+}
+
 const (
 	focusSidebar = iota
 	focusContent
@@ -31,8 +54,15 @@ const (
 	modeFilter
 	modeHelp
 	modeConfirmDelete
+	modeConfirmFollowUp
 	modeReschedule
 	modePriority
+	modeForm
+	modePalette
+	modeTagAdd
+	modeTagRemove
+	modeRenamePreview
+	modeTriage
 )
 
 type DateGroup struct {
@@ -46,6 +76,11 @@ type Model struct {
 	allTasks []Task
 	watcher  *dailyNotesWatcher
 
+	// keys is nil in tests built from a literal Model; use keymap().
+	keys        Keymap
+	pendingKeys []string
+	pendingID   int
+
 	activeView    int
 	focus         int
 	sidebarCursor int
@@ -56,6 +91,22 @@ type Model struct {
 	upcomingGroups  []DateGroup
 	logbookGroups   []DateGroup
 	logbookDayIndex int
+	// periodTasks are the open undated tasks of the current week's and
+	// month's notes, which the Week view shows in their own columns.
+	periodTasks []int
+	// inboxTasks are the open tasks without a due date.
+	inboxTasks []int
+	// triageQueue holds the inbox tasks still to triage, the current one
+	// first.
+	triageQueue []taskRef
+
+	// calendarDays maps a YYYY-MM-DD due date to its open tasks.
+	calendarDays     map[string][]int
+	calendarCursor   time.Time
+	calendarTasks    bool
+	calendarPickFrom time.Time
+
+	boardCol int
 
 	mode                int
 	width               int
@@ -69,7 +120,41 @@ type Model struct {
 
 	selected map[int]bool
 
+	// visual is set while V extends a contiguous range from visualAnchor to
+	// the cursor; visualBase holds the selection from before it started.
+	visual       bool
+	visualAnchor int
+	visualBase   map[int]bool
+
+	// lastClickTask and lastClickAt detect double-clicks.
+	lastClickTask int
+	lastClickAt   time.Time
+
+	// vaultTags counts tags across the whole vault; nil until scanned.
+	vaultTags      map[string]int
+	tagCycle       []string
+	tagCycleIdx    int
+	tagCyclePrefix string
+
+	form      []textinput.Model
+	formFocus int
+	formErr   string
+
+	paletteCursor int
+
+	renamePlan []tagRenameChange
+	renameFrom string
+	renameTo   string
+
 	showPrioritySeparators bool
+	// softWrap shows long descriptions on several lines instead of
+	// truncating them.
+	softWrap   bool
+	showDetail bool
+	// detailContext caches the note lines below the task detailContextFor
+	// for the detail pane.
+	detailContext    []string
+	detailContextFor taskRef
 }
 
 func tagColor(tag string) lipgloss.Color {
@@ -82,9 +167,27 @@ func tagColor(tag string) lipgloss.Color {
 	return lipgloss.Color(colors[h.Sum32()%uint32(len(colors))])
 }
 
+// truncateText shortens s to at most width terminal columns, cutting on a
+// grapheme boundary and marking the cut with "…".
+func truncateText(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	return ansi.Truncate(s, width, "…")
+}
+
+// wrapText breaks s into lines of at most width columns, at spaces where it
+// can and inside words that are wider than a line.
+func wrapText(s string, width int) []string {
+	if width <= 0 {
+		return []string{s}
+	}
+	return strings.Split(ansi.Wrap(s, width, ""), "\n")
+}
+
 func NewModel(cfg Config, tasks []Task) Model {
 	ti := textinput.New()
-	ti.Placeholder = "Task description #tag p1-p5"
+	ti.Placeholder = "Task #tag due fri @scheduled +2w every monday !!"
 	ti.CharLimit = 256
 	ti.Width = 50
 
@@ -97,6 +200,15 @@ func NewModel(cfg Config, tasks []Task) Model {
 		focus:                  focusSidebar,
 		selected:               make(map[int]bool),
 		showPrioritySeparators: true,
+		softWrap:               cfg.Tasks.SoftWrap,
+		calendarCursor:         localToday(),
+	}
+	if keys, err := newKeymap(cfg.Keys); err == nil {
+		m.keys = keys
+	}
+	if len(cfg.Warnings) > 0 {
+		m.statusMsg = "Warning: " + cfg.Warnings[0]
+		m.statusTime = time.Now()
 	}
 	watcher, err := newDailyNotesWatcher(cfg)
 	if err != nil {
@@ -122,7 +234,7 @@ func (m *Model) matchesFilter(t Task) bool {
 			return true
 		}
 	}
-	return false
+	return strings.Contains(strings.ToLower(t.Section), low)
 }
 
 func localToday() time.Time {
@@ -134,8 +246,17 @@ func dueDateAtLocation(dueDate time.Time, location *time.Location) time.Time {
 	return time.Date(dueDate.Year(), dueDate.Month(), dueDate.Day(), 0, 0, 0, 0, location)
 }
 
+// isTaskOverdue reports whether task was due before today. Undated tasks from
+// a weekly or monthly note are only overdue once their period is over.
 func isTaskOverdue(task Task, today time.Time) bool {
-	taskDate := dueDateAtLocation(task.DueDate, today.Location())
+	if task.IsUndated() {
+		return false
+	}
+	due := task.DueDate
+	if !task.Period.End.IsZero() {
+		due = task.Period.End
+	}
+	taskDate := dueDateAtLocation(due, today.Location())
 	return taskDate.Before(today)
 }
 
@@ -148,8 +269,11 @@ func (m *Model) buildViews() {
 	}
 
 	m.todayTasks = nil
+	m.periodTasks = nil
+	m.inboxTasks = nil
 	m.upcomingGroups = nil
 	m.logbookGroups = nil
+	m.calendarDays = make(map[string][]int)
 
 	var todayUndone []int
 	var overdueUndone []int
@@ -173,19 +297,34 @@ func (m *Model) buildViews() {
 			if compDate.IsZero() {
 				compDate = due
 			}
+			if t.IsUndated() && closedDate.IsZero() {
+				// No day to file it under in the logbook.
+				continue
+			}
 			key := compDate.Format("2006-01-02")
 			logbookMap[key] = append(logbookMap[key], i)
 			logbookDates[key] = compDate
 			continue
 		}
 
-		if due.After(today) {
+		if t.IsUndated() {
+			m.inboxTasks = append(m.inboxTasks, i)
+			continue
+		}
+
+		dueKey := due.Format("2006-01-02")
+		m.calendarDays[dueKey] = append(m.calendarDays[dueKey], i)
+
+		switch {
+		case t.Period.contains(today):
+			m.periodTasks = append(m.periodTasks, i)
+		case due.After(today):
 			key := due.Format("2006-01-02")
 			upcomingMap[key] = append(upcomingMap[key], i)
 			upcomingDates[key] = due
-		} else if due.Equal(today) {
+		case due.Equal(today):
 			todayUndone = append(todayUndone, i)
-		} else {
+		default:
 			overdueUndone = append(overdueUndone, i)
 		}
 	}
@@ -229,6 +368,14 @@ func (m *Model) buildViews() {
 			}
 		}
 	}
+	for _, tasks := range m.calendarDays {
+		sortByPriority(tasks)
+	}
+	sortByPriority(m.periodTasks)
+	// The inbox keeps its file order within each priority.
+	sort.SliceStable(m.inboxTasks, func(a, b int) bool {
+		return m.allTasks[m.inboxTasks[a]].Priority < m.allTasks[m.inboxTasks[b]].Priority
+	})
 	m.todayTasks = append(m.todayTasks, todayUndone...)
 	m.todayTasks = append(m.todayTasks, overdueUndone...)
 	sortByTodayPriority(m.todayTasks)
@@ -314,6 +461,26 @@ func (m *Model) currentViewTasks() []int {
 			return m.logbookGroups[m.logbookDayIndex].Tasks
 		}
 		return nil
+	case viewCalendar:
+		day := m.calendarDay()
+		if !m.calendarPickFrom.IsZero() {
+			day = m.calendarPickFrom
+		}
+		return m.calendarDays[day.Format("2006-01-02")]
+	case viewWeek:
+		cols := m.weekColumns()
+		if m.boardCol < len(cols) {
+			return cols[m.boardCol].Tasks
+		}
+		return nil
+	case viewBoard:
+		cols := m.statusColumns()
+		if m.boardCol < len(cols) {
+			return cols[m.boardCol].Tasks
+		}
+		return nil
+	case viewInbox:
+		return m.inboxTasks
 	}
 	return nil
 }
@@ -323,8 +490,8 @@ func (m *Model) clampCursor() {
 	if m.contentCursor >= len(tasks) {
 		m.contentCursor = max(0, len(tasks)-1)
 	}
-	if m.sidebarCursor > 2 {
-		m.sidebarCursor = 2
+	if m.sidebarCursor > len(sidebarItems)-1 {
+		m.sidebarCursor = len(sidebarItems) - 1
 	}
 }
 
@@ -343,11 +510,28 @@ func (m *Model) viewTaskCount(view int) int {
 			return len(m.logbookGroups[m.logbookDayIndex].Tasks)
 		}
 		return 0
+	case viewCalendar:
+		return len(m.calendarDays[m.calendarDay().Format("2006-01-02")])
+	case viewWeek:
+		count := 0
+		for _, col := range m.weekColumns() {
+			if !col.Someday {
+				count += len(col.Tasks)
+			}
+		}
+		return count
+	case viewBoard:
+		return len(m.statusColumns()[1].Tasks)
+	case viewInbox:
+		return len(m.inboxTasks)
 	}
 	return 0
 }
 
 func (m Model) selectedTask() *Task {
+	if m.activeView == viewCalendar && !m.calendarTasks && m.calendarPickFrom.IsZero() {
+		return nil
+	}
 	tasks := m.currentViewTasks()
 	if len(tasks) == 0 || m.contentCursor >= len(tasks) {
 		return nil
@@ -365,6 +549,7 @@ func (m Model) reload() Model {
 	m.err = nil
 	m.allTasks = tasks
 	m.selected = make(map[int]bool)
+	m.detailContextFor = taskRef{}
 	m.buildViews()
 	return m
 }
@@ -383,10 +568,18 @@ func (m *Model) markInternalWrite(status string) {
 }
 
 func (m Model) Init() tea.Cmd {
-	return m.nextWatchCmd()
+	return tea.Batch(m.nextWatchCmd(), m.loadVaultTagsCmd())
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	if nm, ok := next.(Model); ok {
+		next = nm.loadDetailContext()
+	}
+	return next, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -394,6 +587,32 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.input.Width = m.width - 2*hPad - 16
 		return m, nil
 
+	case keyTimeoutMsg:
+		if msg.id != m.pendingID || len(m.pendingKeys) == 0 || m.mode != modeNormal {
+			return m, nil
+		}
+		action, _ := m.keymap().resolve(scopeNormal, m.pendingKeys)
+		m.pendingKeys = nil
+		if action == "" {
+			return m, nil
+		}
+		return m.runAction(action)
+
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
+
+	case externalDoneMsg:
+		return m.handleExternalDone(msg)
+
+	case vaultTagsMsg:
+		if msg.err == nil {
+			m.vaultTags = msg.tags
+		}
+		return m, nil
+
+	case renamePlanMsg:
+		return m.showRenamePlan(msg), nil
+
 	case fileWatchMsg:
 		cmd := m.nextWatchCmd()
 		if msg.err != nil {
@@ -412,85 +631,99 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 
 	case tea.KeyMsg:
-		if m.mode == modeNewTask || m.mode == modeEditTask || m.mode == modeFilter || m.mode == modeReschedule {
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		if m.mode == modeNewTask || m.mode == modeEditTask || m.mode == modeFilter || m.mode == modeReschedule ||
+			m.mode == modeTagAdd || m.mode == modeTagRemove || m.mode == modeTriage {
 			return m.handleInputMode(msg)
 		}
 		if m.mode == modeHelp {
 			m.mode = modeNormal
 			return m, nil
 		}
-		if m.mode == modeConfirmDelete {
-			return m.handleConfirmDelete(msg)
+		if m.mode == modeConfirmDelete || m.mode == modeConfirmFollowUp {
+			return m.handleConfirm(msg)
 		}
 		if m.mode == modePriority {
 			return m.handlePriority(msg)
 		}
+		if m.mode == modeForm {
+			return m.handleForm(msg)
+		}
+		if m.mode == modePalette {
+			return m.handlePalette(msg)
+		}
+		if m.mode == modeRenamePreview {
+			return m.handleRenamePreview(msg)
+		}
 		return m.handleNormalMode(msg)
 	}
 
 	return m, nil
 }
 
-func (m Model) handleConfirmDelete(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "y", "Y":
-		task := m.selectedTask()
-		if task != nil {
-			if err := CancelTask(task); err != nil {
-				m.err = err
-				m.statusMsg = "Error: " + err.Error()
-			} else {
-				m.markInternalWrite("Task cancelled")
-				m = m.reload()
-			}
+// handleConfirm answers the cancel and bulk follow-up prompts.
+func (m Model) handleConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	km := m.keymap()
+	switch {
+	case km.is("confirm_yes", msg):
+		if m.mode == modeConfirmFollowUp {
+			m = m.followUpTargets()
+		} else {
+			m = m.cancelTargets()
 		}
 		m.mode = modeNormal
-	case "n", "N", "esc", "q":
+	case km.is("confirm_no", msg):
 		m.mode = modeNormal
 	}
 	return m, nil
 }
 
+// priorityActions maps the priority prompt's actions to their levels, in
+// prompt order.
+var priorityActions = []struct {
+	action   string
+	priority int
+}{
+	{"priority_highest", PriorityHighest},
+	{"priority_high", PriorityHigh},
+	{"priority_medium", PriorityMedium},
+	{"priority_low", PriorityLow},
+	{"priority_lowest", PriorityLowest},
+	{"priority_none", PriorityNone},
+}
+
 func (m Model) handlePriority(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	priorityMap := map[string]int{
-		"1": PriorityHighest,
-		"2": PriorityHigh,
-		"3": PriorityMedium,
-		"4": PriorityLow,
-		"5": PriorityLowest,
-		"0": PriorityNone,
-	}
-	labels := map[int]string{
-		PriorityHighest: "Highest",
-		PriorityHigh:    "High",
-		PriorityMedium:  "Medium",
-		PriorityLow:     "Low",
-		PriorityLowest:  "Lowest",
-		PriorityNone:    "None",
-	}
-
-	key := msg.String()
-	if p, ok := priorityMap[key]; ok {
-		task := m.selectedTask()
-		if task != nil {
-			if err := SetPriority(task, p); err != nil {
-				m.statusMsg = "Error: " + err.Error()
-			} else {
-				m.markInternalWrite("Priority → " + labels[p])
-				m = m.reload()
-			}
+	km := m.keymap()
+	for _, pa := range priorityActions {
+		if !km.is(pa.action, msg) {
+			continue
 		}
 		m.mode = modeNormal
-	} else if key == "esc" || key == "q" {
+		return m.setTargetsPriority(pa.priority), nil
+	}
+	if km.is("priority_dismiss", msg) {
 		m.mode = modeNormal
 	}
 	return m, nil
 }
 
 func (m Model) handleInputMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "tab" && (m.mode == modeNewTask || m.mode == modeEditTask || m.mode == modeTagAdd || m.mode == modeTagRemove) {
+		m.completeTag(&m.input)
+		return m, nil
+	}
+	m.tagCycle = nil
+
 	switch msg.String() {
 	case "esc":
+		if m.mode == modeReschedule && !m.calendarPickFrom.IsZero() {
+			m.calendarCursor = m.calendarPickFrom
+			m.calendarPickFrom = time.Time{}
+		}
 		m.mode = modeNormal
+		m.triageQueue = nil
 		m.input.Blur()
 		return m, nil
 
@@ -505,37 +738,7 @@ func (m Model) handleInputMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if value == "" {
 				return m, nil
 			}
-			dueDate := localToday()
-			if m.activeView == viewUpcoming && len(m.upcomingGroups) > 0 {
-				groupIdx := m.groupIndexForCursor()
-				if groupIdx >= 0 && groupIdx < len(m.upcomingGroups) {
-					dueDate = m.upcomingGroups[groupIdx].Date
-				}
-			}
-			if dm := dueDateRe.FindStringSubmatch(value); dm != nil {
-				if t, err := time.Parse("2006-01-02", dm[1]); err == nil {
-					dueDate = t
-				}
-				value = dueDateRe.ReplaceAllString(value, "")
-				value = strings.TrimSpace(value)
-			}
-			priority := PriorityNone
-			pMap := map[string]int{"p1": PriorityHighest, "p2": PriorityHigh, "p3": PriorityMedium, "p4": PriorityLow, "p5": PriorityLowest}
-			for token, p := range pMap {
-				if strings.Contains(" "+value+" ", " "+token+" ") {
-					priority = p
-					value = strings.Replace(value, token, "", 1)
-					value = strings.TrimSpace(value)
-					break
-				}
-			}
-			if err := CreateTask(m.cfg, value, dueDate, priority); err != nil {
-				m.err = err
-				m.statusMsg = "Error: " + err.Error()
-			} else {
-				m.markInternalWrite("Task created")
-				m = m.reload()
-			}
+			m = m.createQuickAddTask(value)
 
 		case modeEditTask:
 			m.mode = modeNormal
@@ -546,7 +749,9 @@ func (m Model) handleInputMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if task == nil {
 				return m, nil
 			}
-			newLine := buildTaskLine(value, task.Tags, task.Priority, task.DueDate, task.Done, task.Cancelled, task.CompletionDate, task.CancelledDate)
+			edited := *task
+			edited.Description = value
+			newLine := formatTaskLine(edited)
 			if err := UpdateTaskLine(task, newLine); err != nil {
 				m.err = err
 				m.statusMsg = "Error: " + err.Error()
@@ -560,43 +765,33 @@ func (m Model) handleInputMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.filter = value
 			m.buildViews()
 
+		case modeTagAdd:
+			m.mode = modeNormal
+			m = m.editTargetTags(parseTagList(value), nil)
+
+		case modeTagRemove:
+			m.mode = modeNormal
+			m = m.editTargetTags(nil, parseTagList(value))
+
 		case modeReschedule:
 			m.mode = modeNormal
+			pickFrom := m.calendarPickFrom
+			m.calendarPickFrom = time.Time{}
 			if value == "" {
 				return m, nil
 			}
-			newDate, err := parseRelativeDate(value)
+			newDate, err := m.parseDate(value)
 			if err != nil {
 				m.statusMsg = "Invalid date: " + value
 				m.statusTime = time.Now()
 				return m, nil
 			}
-			if len(m.selected) > 0 {
-				count := 0
-				for idx := range m.selected {
-					if err := RescheduleTask(&m.allTasks[idx], newDate); err != nil {
-						m.statusMsg = "Error: " + err.Error()
-						m.statusTime = time.Now()
-						break
-					}
-					count++
-				}
-				m.selected = make(map[int]bool)
-				m.markInternalWrite(fmt.Sprintf("%d tasks → %s", count, newDate.Format("Jan 02")))
-				m = m.reload()
-			} else {
-				task := m.selectedTask()
-				if task == nil {
-					return m, nil
-				}
-				if err := RescheduleTask(task, newDate); err != nil {
-					m.err = err
-					m.statusMsg = "Error: " + err.Error()
-				} else {
-					m.markInternalWrite("Rescheduled → " + newDate.Format("Jan 02"))
-					m = m.reload()
-				}
-			}
+			m.calendarPickFrom = pickFrom
+			m = m.rescheduleSelection(newDate)
+			m.calendarPickFrom = time.Time{}
+
+		case modeTriage:
+			m = m.triageAnswer(value)
 		}
 		return m, nil
 	}
@@ -606,6 +801,60 @@ func (m Model) handleInputMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// createQuickAddTask parses value with the quick-add syntax and writes the
+// task to its daily note.
+func (m Model) createQuickAddTask(value string) Model {
+	task := parseQuickAdd(value, m.newTaskDefaultDate(), m.parseDate)
+	if task.Description == "" {
+		m.statusMsg = "Task needs a description"
+		m.statusTime = time.Now()
+		return m
+	}
+	warning := m.newTagWarning(task.Tags)
+	if err := CreateTask(m.cfg, task); err != nil {
+		m.err = err
+		m.statusMsg = "Error: " + err.Error()
+		return m
+	}
+	if warning != "" {
+		m.markInternalWrite("Task created · " + warning)
+	} else {
+		m.markInternalWrite("Task created")
+	}
+	return m.reload()
+}
+
+// rescheduleSelection moves the selected tasks, or the task under the cursor
+// when nothing is selected, to newDate.
+func (m Model) rescheduleSelection(newDate time.Time) Model {
+	if len(m.selected) > 0 {
+		count := 0
+		for idx := range m.selected {
+			if err := RescheduleTask(&m.allTasks[idx], newDate); err != nil {
+				m.statusMsg = "Error: " + err.Error()
+				m.statusTime = time.Now()
+				break
+			}
+			count++
+		}
+		m.selected = make(map[int]bool)
+		m.markInternalWrite(fmt.Sprintf("%d tasks → %s", count, newDate.Format("Jan 02")))
+		return m.reload()
+	}
+
+	task := m.selectedTask()
+	if task == nil {
+		return m
+	}
+	if err := RescheduleTask(task, newDate); err != nil {
+		m.err = err
+		m.statusMsg = "Error: " + err.Error()
+		return m
+	}
+	m.markInternalWrite("Rescheduled → " + newDate.Format("Jan 02"))
+	return m.reload()
+}
+
 func (m *Model) groupIndexForCursor() int {
 	cursor := m.contentCursor
 	var groups []DateGroup
@@ -627,54 +876,110 @@ func (m *Model) groupIndexForCursor() int {
 	return len(groups) - 1
 }
 
+// setActiveView switches to view, resetting the cursor and selection.
+func (m *Model) setActiveView(view int) {
+	m.activeView = view
+	m.sidebarCursor = view
+	m.contentCursor = 0
+	m.scrollOffset = 0
+	m.selected = make(map[int]bool)
+	m.calendarTasks = false
+	m.calendarPickFrom = time.Time{}
+	m.boardCol = m.firstBoardCol()
+}
+
+// firstBoardCol is the column a board view opens on: today's in the week
+// board.
+func (m Model) firstBoardCol() int {
+	if m.activeView == viewWeek {
+		return m.todayWeekColumn()
+	}
+	return 0
+}
+
 func (m Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "q", "ctrl+c":
+	actions, cmd := m.resolveNormalKey(msg)
+	cmds := []tea.Cmd{cmd}
+	for _, action := range actions {
+		next, cmd := m.runAction(action)
+		m = next.(Model)
+		cmds = append(cmds, cmd)
+		m = m.updateVisual(action)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// runAction performs a normal-mode action, letting the calendar and board
+// views claim it first.
+func (m Model) runAction(action string) (tea.Model, tea.Cmd) {
+	if m.activeView == viewCalendar && m.focus == focusContent {
+		if next, cmd, handled := m.handleCalendarAction(action); handled {
+			return next, cmd
+		}
+	}
+	if m.activeView == viewWeek && m.focus == focusContent {
+		if next, handled := m.handleWeekAction(action); handled {
+			return next, nil
+		}
+	}
+	if m.activeView == viewBoard && m.focus == focusContent {
+		if next, handled := m.handleBoardAction(action); handled {
+			return next, nil
+		}
+	}
+
+	switch action {
+	case "quit":
 		return m, tea.Quit
 
-	case "1":
-		m.activeView = viewToday
-		m.sidebarCursor = 0
-		m.contentCursor = 0
-		m.scrollOffset = 0
-		m.selected = make(map[int]bool)
+	case "view_today":
+		m.setActiveView(viewToday)
 
-	case "2":
-		m.activeView = viewUpcoming
-		m.sidebarCursor = 1
-		m.contentCursor = 0
-		m.scrollOffset = 0
-		m.selected = make(map[int]bool)
+	case "view_upcoming":
+		m.setActiveView(viewUpcoming)
 
-	case "3":
-		m.activeView = viewLogbook
-		m.sidebarCursor = 2
-		m.contentCursor = 0
-		m.scrollOffset = 0
-		m.selected = make(map[int]bool)
+	case "view_logbook":
+		m.setActiveView(viewLogbook)
+
+	case "view_calendar":
+		m.setActiveView(viewCalendar)
+
+	case "view_week":
+		m.setActiveView(viewWeek)
+
+	case "view_board":
+		m.setActiveView(viewBoard)
+
+	case "view_inbox":
+		m.setActiveView(viewInbox)
 
-	case "tab":
+	case "triage":
+		return m.startTriage()
+
+	case "toggle_focus":
 		if m.focus == focusSidebar {
 			m.focus = focusContent
 		} else {
 			m.focus = focusSidebar
 		}
 
-	case "h":
+	case "left":
 		m.focus = focusSidebar
 
-	case "l":
+	case "right":
 		if m.focus == focusSidebar {
 			m.focus = focusContent
 		}
 
-	case "j", "down":
+	case "down":
 		if m.focus == focusSidebar {
-			if m.sidebarCursor < 2 {
+			if m.sidebarCursor < len(sidebarItems)-1 {
 				m.sidebarCursor++
 				m.activeView = m.sidebarCursor
 				m.contentCursor = 0
 				m.scrollOffset = 0
+				m.calendarTasks = false
+				m.boardCol = m.firstBoardCol()
 			}
 		} else {
 			tasks := m.currentViewTasks()
@@ -683,13 +988,15 @@ func (m Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-	case "k", "up":
+	case "up":
 		if m.focus == focusSidebar {
 			if m.sidebarCursor > 0 {
 				m.sidebarCursor--
 				m.activeView = m.sidebarCursor
 				m.contentCursor = 0
 				m.scrollOffset = 0
+				m.calendarTasks = false
+				m.boardCol = m.firstBoardCol()
 			}
 		} else {
 			if m.contentCursor > 0 {
@@ -697,7 +1004,17 @@ func (m Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-	case "enter":
+	case "top":
+		if m.focus == focusContent {
+			m.contentCursor = 0
+		}
+
+	case "bottom":
+		if m.focus == focusContent {
+			m.contentCursor = max(0, len(m.currentViewTasks())-1)
+		}
+
+	case "open":
 		if m.focus == focusSidebar {
 			m.focus = focusContent
 		} else {
@@ -720,7 +1037,7 @@ func (m Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-	case " ":
+	case "select":
 		if m.focus == focusContent && m.activeView != viewLogbook {
 			tasks := m.currentViewTasks()
 			if len(tasks) > 0 && m.contentCursor < len(tasks) {
@@ -736,7 +1053,29 @@ func (m Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-	case "v":
+	case "open_editor":
+		if m.focus == focusContent {
+			return m.openInEditor()
+		}
+
+	case "open_obsidian":
+		if m.focus == focusContent {
+			return m.openInObsidian()
+		}
+
+	case "visual":
+		calendarGrid := m.activeView == viewCalendar && !m.calendarTasks
+		if m.focus == focusContent && m.activeView != viewLogbook && !calendarGrid && len(m.currentViewTasks()) > 0 {
+			if m.visual {
+				m.visual = false
+			} else {
+				m.visual = true
+				m.visualAnchor = m.contentCursor
+				m.visualBase = maps.Clone(m.selected)
+			}
+		}
+
+	case "select_all":
 		if m.focus == focusContent && m.activeView != viewLogbook {
 			tasks := m.currentViewTasks()
 			if len(m.selected) > 0 {
@@ -748,7 +1087,7 @@ func (m Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-	case "d":
+	case "done":
 		if m.focus == focusContent {
 			if len(m.selected) > 0 && m.activeView != viewLogbook {
 				count := 0
@@ -784,39 +1123,30 @@ func (m Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-	case "f", "F":
+	case "follow_up":
 		if m.focus == focusContent && m.activeView != viewLogbook {
-			task := m.selectedTask()
-			if task != nil {
-				followUpDate, err := CreateFollowUpTask(m.cfg, *task)
-				if err != nil {
-					m.err = err
-					m.statusMsg = "Error: " + err.Error()
-				} else {
-					m.markInternalWrite("Follow-up → " + followUpDate.Format("Jan 02"))
-					m = m.reload()
-				}
+			if len(m.selected) > 0 {
+				m.mode = modeConfirmFollowUp
+			} else {
+				m = m.followUpTargets()
 			}
 		}
 
-	case "D":
-		if m.focus == focusContent && m.activeView != viewLogbook {
-			task := m.selectedTask()
-			if task != nil {
-				m.mode = modeConfirmDelete
-			}
+	case "cancel":
+		if m.focus == focusContent && m.activeView != viewLogbook && len(m.targets()) > 0 {
+			m.mode = modeConfirmDelete
 		}
 
-	case "n":
+	case "new":
 		if m.activeView != viewLogbook {
 			m.mode = modeNewTask
-			m.input.Placeholder = "Task description #tag p1-p5"
+			m.input.Placeholder = "Task #tag due fri @scheduled +2w every monday !!"
 			m.input.SetValue("")
 			m.input.Focus()
 			return m, m.input.Cursor.BlinkCmd()
 		}
 
-	case "e":
+	case "edit":
 		if m.focus == focusContent && m.activeView != viewLogbook {
 			task := m.selectedTask()
 			if task != nil {
@@ -828,15 +1158,20 @@ func (m Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-	case "p":
+	case "edit_form":
 		if m.focus == focusContent && m.activeView != viewLogbook {
 			task := m.selectedTask()
 			if task != nil {
-				m.mode = modePriority
+				return m.openEditForm(*task)
 			}
 		}
 
-	case "s":
+	case "priority":
+		if m.focus == focusContent && m.activeView != viewLogbook && len(m.targets()) > 0 {
+			m.mode = modePriority
+		}
+
+	case "reschedule":
 		if m.focus == focusContent && m.activeView != viewLogbook {
 			if len(m.selected) > 0 {
 				m.mode = modeReschedule
@@ -855,14 +1190,14 @@ func (m Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-	case "/":
+	case "filter":
 		m.mode = modeFilter
 		m.input.Placeholder = "Filter tasks..."
 		m.input.SetValue(m.filter)
 		m.input.Focus()
 		return m, m.input.Cursor.BlinkCmd()
 
-	case "esc":
+	case "clear":
 		if len(m.selected) > 0 {
 			m.selected = make(map[int]bool)
 		} else if m.filter != "" {
@@ -870,7 +1205,7 @@ func (m Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.buildViews()
 		}
 
-	case "left":
+	case "prev_day":
 		if m.activeView == viewLogbook && len(m.logbookGroups) > 0 {
 			if m.logbookDayIndex < len(m.logbookGroups)-1 {
 				m.logbookDayIndex++
@@ -879,17 +1214,33 @@ func (m Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-	case "right":
+	case "next_day":
 		if m.activeView == viewLogbook && m.logbookDayIndex > 0 {
 			m.logbookDayIndex--
 			m.contentCursor = 0
 			m.scrollOffset = 0
 		}
 
-	case "?":
+	case "help":
 		m.mode = modeHelp
 
-	case "t":
+	case "palette":
+		return m.openPalette()
+
+	case "tag_add":
+		if m.focus == focusContent && m.activeView != viewLogbook {
+			return m.openTagInput(modeTagAdd)
+		}
+
+	case "tag_remove":
+		if m.focus == focusContent && m.activeView != viewLogbook {
+			return m.openTagInput(modeTagRemove)
+		}
+
+	case "details":
+		m.showDetail = !m.showDetail
+
+	case "separators":
 		m.showPrioritySeparators = !m.showPrioritySeparators
 		state := "off"
 		if m.showPrioritySeparators {
@@ -898,7 +1249,16 @@ func (m Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.statusMsg = "Priority separators " + state
 		m.statusTime = time.Now()
 
-	case "r":
+	case "wrap":
+		m.softWrap = !m.softWrap
+		state := "off"
+		if m.softWrap {
+			state = "on"
+		}
+		m.statusMsg = "Soft wrap " + state
+		m.statusTime = time.Now()
+
+	case "reload":
 		m = m.reload()
 		if m.err == nil {
 			m.statusMsg = "Reloaded"
@@ -930,21 +1290,31 @@ func (m Model) View() string {
 	if m.mode == modeHelp {
 		return m.renderHelp()
 	}
+	if m.mode == modeForm {
+		return m.renderForm()
+	}
+	if m.mode == modePalette {
+		return m.renderPalette()
+	}
+	if m.mode == modeRenamePreview {
+		return m.renderRenamePreview()
+	}
 
-	totalWidth := m.width - 2*hPad - 2
-	sidebarWidth := 22
-	contentWidth := totalWidth - sidebarWidth - 1
-	contentHeight := m.height - 4
+	totalWidth, sidebarWidth, contentWidth, detailWidth, contentHeight := m.paneLayout()
 
 	sidebar := m.renderSidebar(sidebarWidth, contentHeight)
 	content := m.renderContent(contentWidth, contentHeight)
 
 	board := lipgloss.JoinHorizontal(lipgloss.Top, sidebar, content)
+	if m.showDetail {
+		board = lipgloss.JoinHorizontal(lipgloss.Top, board, m.renderDetail(detailWidth, contentHeight))
+	}
 
 	footer := m.renderFooter(totalWidth)
 
 	var inputArea string
-	if m.mode == modeNewTask || m.mode == modeEditTask || m.mode == modeFilter || m.mode == modeReschedule {
+	if m.mode == modeNewTask || m.mode == modeEditTask || m.mode == modeFilter || m.mode == modeReschedule ||
+		m.mode == modeTagAdd || m.mode == modeTagRemove || m.mode == modeTriage {
 		prefix := " New: "
 		if m.mode == modeEditTask {
 			prefix = " Edit: "
@@ -952,31 +1322,78 @@ func (m Model) View() string {
 			prefix = " Filter: "
 		} else if m.mode == modeReschedule {
 			prefix = " Reschedule: "
+		} else if m.mode == modeTagAdd {
+			prefix = fmt.Sprintf(" Add tags (%d): ", len(m.targets()))
+		} else if m.mode == modeTagRemove {
+			prefix = fmt.Sprintf(" Remove tags (%d): ", len(m.targets()))
+		} else if m.mode == modeTriage {
+			prefix = m.triagePrompt(totalWidth / 2)
 		}
 		prefixStyle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color(m.cfg.Theme.Accent)).
 			Bold(true)
 		inputArea = "\n" + prefixStyle.Render(prefix) + m.input.View()
+		if m.mode == modeNewTask || m.mode == modeEditTask {
+			inputArea += m.renderQuickAddPreview()
+		} else if m.mode == modeTagAdd || m.mode == modeTagRemove {
+			if line := m.renderTagSuggestions(m.input); line != "" {
+				inputArea += "\n" + line
+			}
+		}
 	}
 
-	if m.mode == modeConfirmDelete {
+	if m.mode == modeConfirmDelete || m.mode == modeConfirmFollowUp {
 		confirmStyle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color(m.cfg.Theme.Overdue)).
 			Bold(true)
-		inputArea = "\n" + confirmStyle.Render(" Cancel this task? [y/n]")
+		km := m.keymap()
+		question := "Cancel this task?"
+		if m.mode == modeConfirmFollowUp {
+			question = "Create follow-ups for " + m.targetSummary() + "?"
+		} else if len(m.selected) > 0 {
+			question = "Cancel " + m.targetSummary() + "?"
+		}
+		inputArea = "\n" + confirmStyle.Render(fmt.Sprintf(" %s [%s/%s]", question, km.label("confirm_yes"), km.label("confirm_no")))
 	}
 
 	if m.mode == modePriority {
 		prStyle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color(m.cfg.Theme.Accent)).
 			Bold(true)
-		inputArea = "\n" + prStyle.Render(" Priority: 1🔺 2⏫ 3🔼 4🔽 5⏬ 0 none")
+		km := m.keymap()
+		prompt := " Priority:"
+		if len(m.selected) > 0 {
+			prompt = " Priority for " + m.targetSummary() + ":"
+		}
+		for _, pa := range priorityActions {
+			symbol := priorityEmojis[pa.priority]
+			if pa.priority == PriorityNone {
+				symbol = " none"
+			}
+			prompt += " " + km.label(pa.action) + symbol
+		}
+		inputArea = "\n" + prStyle.Render(prompt)
 	}
 
 	result := board + "\n" + footer + inputArea
 	return lipgloss.NewStyle().Padding(0, hPad).Render(result)
 }
 
+// paneLayout returns the widths of the main panes and their shared height,
+// for View and for mapping mouse clicks back onto them.
+func (m Model) paneLayout() (totalWidth, sidebarWidth, contentWidth, detailWidth, contentHeight int) {
+	totalWidth = m.width - 2*hPad - 2
+	sidebarWidth = 22
+	contentWidth = totalWidth - sidebarWidth - 1
+	contentHeight = m.height - 4
+
+	if m.showDetail {
+		detailWidth = max(30, contentWidth*2/5)
+		contentWidth -= detailWidth + 2
+	}
+	return totalWidth, sidebarWidth, contentWidth, detailWidth, contentHeight
+}
+
 func (m Model) renderSidebar(width, height int) string {
 	isActive := m.focus == focusSidebar
 	accent := lipgloss.Color(m.cfg.Theme.Accent)
@@ -985,21 +1402,10 @@ func (m Model) renderSidebar(width, height int) string {
 		borderColor = accent
 	}
 
-	type sidebarItem struct {
-		icon  string
-		label string
-		view  int
-	}
-	items := []sidebarItem{
-		{"☀️", "Today", viewToday},
-		{"📅", "Upcoming", viewUpcoming},
-		{"📓", "Logbook", viewLogbook},
-	}
-
 	var rows []string
 	rows = append(rows, "")
 
-	for _, item := range items {
+	for _, item := range sidebarItems {
 		count := m.viewTaskCount(item.view)
 		selected := m.activeView == item.view
 
@@ -1057,6 +1463,14 @@ func (m Model) renderContent(width, height int) string {
 		body = m.renderUpcomingView(width-4, viewportHeight)
 	case viewLogbook:
 		body = m.renderLogbookView(width-4, viewportHeight)
+	case viewCalendar:
+		body = m.renderCalendarView(width-4, viewportHeight)
+	case viewWeek:
+		body = m.renderWeekView(width-4, viewportHeight)
+	case viewBoard:
+		body = m.renderBoardView(width-4, viewportHeight)
+	case viewInbox:
+		body = m.renderInboxView(width-4, viewportHeight)
 	}
 
 	paneStyle := lipgloss.NewStyle().
@@ -1075,6 +1489,13 @@ func (m Model) renderTodayView(maxWidth, maxHeight int) string {
 }
 
 func (m Model) renderTodayRows(maxWidth int) ([]string, int) {
+	rows, selectedLine, _ := m.todayLayout(maxWidth)
+	return rows, selectedLine
+}
+
+// todayLayout renders the Today rows along with the task index shown on each
+// row, or -1 for titles, separators and blank lines.
+func (m Model) todayLayout(maxWidth int) ([]string, int, []int) {
 	today := localToday()
 	titleStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color(m.cfg.Theme.Accent)).
@@ -1092,7 +1513,7 @@ func (m Model) renderTodayRows(maxWidth int) ([]string, int) {
 			Italic(true).
 			PaddingLeft(2)
 		rows = append(rows, emptyStyle.Render("No tasks for today"))
-		return rows, selectedLine
+		return rows, selectedLine, nil
 	}
 
 	selectedTaskIdx := -1
@@ -1100,19 +1521,21 @@ func (m Model) renderTodayRows(maxWidth int) ([]string, int) {
 		selectedTaskIdx = m.todayTasks[m.contentCursor]
 	}
 
-	taskRows, taskSelectedLine := m.renderPrioritySeparatedRows(m.todayTasks, maxWidth, isActive, selectedTaskIdx, func(task Task) bool {
+	taskRows, taskSelectedLine, taskLines := m.renderPrioritySeparatedRows(m.todayTasks, maxWidth, isActive, selectedTaskIdx, func(task Task) bool {
 		return isTaskOverdue(task, today)
 	})
 	if taskSelectedLine >= 0 {
 		selectedLine = len(rows) + taskSelectedLine
 	}
+	lineTasks := append(blankLines(len(rows)), taskLines...)
 	rows = append(rows, taskRows...)
 
 	if len(taskRows) != len(m.todayTasks) {
 		rows = append(rows, "")
+		lineTasks = append(lineTasks, -1)
 	}
 
-	return rows, selectedLine
+	return rows, selectedLine, lineTasks
 }
 
 func (m Model) renderUpcomingView(maxWidth, maxHeight int) string {
@@ -1122,6 +1545,12 @@ func (m Model) renderUpcomingView(maxWidth, maxHeight int) string {
 }
 
 func (m Model) renderUpcomingRows(maxWidth int) ([]string, int) {
+	rows, selectedLine, _ := m.upcomingLayout(maxWidth)
+	return rows, selectedLine
+}
+
+// upcomingLayout is todayLayout for the Upcoming view.
+func (m Model) upcomingLayout(maxWidth int) ([]string, int, []int) {
 	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.cfg.Theme.Accent)).Bold(true)
 	title := titleStyle.Render("  Upcoming")
 
@@ -1142,28 +1571,37 @@ func (m Model) renderUpcomingRows(maxWidth int) ([]string, int) {
 			Italic(true).
 			PaddingLeft(2)
 		rows = append(rows, emptyStyle.Render("Nothing upcoming"))
-		return rows, selectedLine
+		return rows, selectedLine, nil
 	}
 
+	lineTasks := blankLines(len(rows))
 	for _, g := range m.upcomingGroups {
 		headerStyle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color(m.cfg.Theme.Upcoming))
-		header := fmt.Sprintf("  ── %s %s", g.Label, strings.Repeat("─", max(0, maxWidth-len(g.Label)-6)))
+		header := fmt.Sprintf("  ── %s %s", g.Label, strings.Repeat("─", max(0, maxWidth-lipgloss.Width(g.Label)-6)))
 		rows = append(rows, headerStyle.Render(header))
+		lineTasks = append(lineTasks, -1)
 
-		taskRows, taskLine := m.renderPrioritySeparatedRows(g.Tasks, maxWidth, isActive, selectedTaskIdx, func(Task) bool { return false })
+		taskRows, taskLine, taskLines := m.renderPrioritySeparatedRows(g.Tasks, maxWidth, isActive, selectedTaskIdx, func(Task) bool { return false })
 		if taskLine >= 0 {
 			taskLine += len(rows)
 			selectedLine = taskLine
 		}
 		rows = append(rows, taskRows...)
 		rows = append(rows, "")
+		lineTasks = append(append(lineTasks, taskLines...), -1)
 	}
 
-	return rows, selectedLine
+	return rows, selectedLine, lineTasks
 }
 
 func (m Model) renderLogbookRows(maxWidth int) ([]string, int) {
+	rows, selectedLine, _ := m.logbookLayout(maxWidth)
+	return rows, selectedLine
+}
+
+// logbookLayout is todayLayout for the Logbook view.
+func (m Model) logbookLayout(maxWidth int) ([]string, int, []int) {
 	if len(m.logbookGroups) == 0 {
 		titleStyle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color(m.cfg.Theme.Accent)).
@@ -1177,7 +1615,7 @@ func (m Model) renderLogbookRows(maxWidth int) ([]string, int) {
 			"",
 			emptyStyle.Render("Logbook is empty"),
 		}
-		return rows, -1
+		return rows, -1, nil
 	}
 
 	g := m.logbookGroups[m.logbookDayIndex]
@@ -1215,6 +1653,7 @@ func (m Model) renderLogbookRows(maxWidth int) ([]string, int) {
 	counter := counterStyle.Render(fmt.Sprintf("  %d/%d days", m.logbookDayIndex+1, len(m.logbookGroups)))
 
 	rows := []string{"  " + title, counter, ""}
+	lineTasks := blankLines(len(rows))
 	selectedLine := -1
 
 	for i, taskIdx := range g.Tasks {
@@ -1225,6 +1664,7 @@ func (m Model) renderLogbookRows(maxWidth int) ([]string, int) {
 			selectedLine = len(rows)
 		}
 		rows = append(rows, row)
+		lineTasks = append(lineTasks, taskIdx)
 	}
 
 	if len(g.Tasks) == 0 {
@@ -1235,7 +1675,7 @@ func (m Model) renderLogbookRows(maxWidth int) ([]string, int) {
 		rows = append(rows, emptyStyle.Render("No closed tasks"))
 	}
 
-	return rows, selectedLine
+	return rows, selectedLine, lineTasks
 }
 
 func (m Model) renderLogbookView(maxWidth, maxHeight int) string {
@@ -1257,7 +1697,7 @@ func prioritySectionLabel(priority int) (label string, color string) {
 
 func (m Model) renderPrioritySeparator(label string, maxWidth int, color string) string {
 	sep := fmt.Sprintf("  ── %s ", label)
-	pad := max(0, maxWidth-len(sep)-6)
+	pad := max(0, maxWidth-lipgloss.Width(sep)-6)
 	line := fmt.Sprintf("  ── %s %s", label, strings.Repeat("─", pad))
 	return lipgloss.NewStyle().
 		Foreground(lipgloss.Color(color)).
@@ -1265,12 +1705,13 @@ func (m Model) renderPrioritySeparator(label string, maxWidth int, color string)
 		Render(line)
 }
 
-func (m Model) renderPrioritySeparatedRows(taskIndices []int, maxWidth int, isActive bool, selectedTaskIdx int, isOverdue func(Task) bool) ([]string, int) {
+func (m Model) renderPrioritySeparatedRows(taskIndices []int, maxWidth int, isActive bool, selectedTaskIdx int, isOverdue func(Task) bool) ([]string, int, []int) {
 	if len(taskIndices) == 0 {
-		return nil, -1
+		return nil, -1, nil
 	}
 
 	var rows []string
+	var lineTasks []int
 	selectedRow := -1
 	previous := ""
 
@@ -1280,6 +1721,7 @@ func (m Model) renderPrioritySeparatedRows(taskIndices []int, maxWidth int, isAc
 		rows = append(rows, "")
 		rows = append(rows, m.renderPrioritySeparator(firstSection, maxWidth, firstColor))
 		rows = append(rows, "")
+		lineTasks = blankLines(3)
 		previous = firstSection
 	}
 
@@ -1290,6 +1732,7 @@ func (m Model) renderPrioritySeparatedRows(taskIndices []int, maxWidth int, isAc
 			rows = append(rows, "")
 			rows = append(rows, m.renderPrioritySeparator(section, maxWidth, color))
 			rows = append(rows, "")
+			lineTasks = append(lineTasks, -1, -1, -1)
 		}
 		selected := isActive && taskIdx == selectedTaskIdx
 		isOverdueTask := false
@@ -1301,10 +1744,20 @@ func (m Model) renderPrioritySeparatedRows(taskIndices []int, maxWidth int, isAc
 			selectedRow = len(rows)
 		}
 		rows = append(rows, row)
+		lineTasks = append(lineTasks, taskIdx)
 		previous = section
 	}
 
-	return rows, selectedRow
+	return rows, selectedRow, lineTasks
+}
+
+// blankLines returns n row entries that show no task.
+func blankLines(n int) []int {
+	lines := make([]int, n)
+	for i := range lines {
+		lines[i] = -1
+	}
+	return lines
 }
 
 func (m Model) scrollRows(rows []string, selectedLine int, maxHeight int) []string {
@@ -1312,12 +1765,43 @@ func (m Model) scrollRows(rows []string, selectedLine int, maxHeight int) []stri
 		return nil
 	}
 
+	lines, first, span, _ := flattenRows(rows, selectedLine)
+	offset := m.scrollOffsetFor(len(lines), first, span, maxHeight)
+	end := offset + maxHeight
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	return lines[offset:end]
+}
+
+// flattenRows splits rows that span several lines, such as soft-wrapped
+// tasks, into single lines. It returns the line the selected row starts on,
+// how many lines it spans, and the row each line came from.
+func flattenRows(rows []string, selectedRow int) (lines []string, first, span int, owner []int) {
+	first, span = -1, 1
+	for i, row := range rows {
+		parts := strings.Split(row, "\n")
+		if i == selectedRow {
+			first, span = len(lines), len(parts)
+		}
+		for range parts {
+			owner = append(owner, i)
+		}
+		lines = append(lines, parts...)
+	}
+	return lines, first, span, owner
+}
+
+// scrollOffsetFor returns the first visible line when total lines are shown
+// in maxHeight lines with the span lines from selectedLine kept in view.
+func (m Model) scrollOffsetFor(total, selectedLine, span, maxHeight int) int {
 	offset := m.scrollOffset
 	if offset < 0 {
 		offset = 0
 	}
 
-	maxOffset := len(rows) - maxHeight
+	maxOffset := total - maxHeight
 	if maxOffset < 0 {
 		maxOffset = 0
 	}
@@ -1329,8 +1813,11 @@ func (m Model) scrollRows(rows []string, selectedLine int, maxHeight int) []stri
 		if selectedLine < offset {
 			offset = selectedLine
 		}
-		if selectedLine >= offset+maxHeight {
-			offset = selectedLine - maxHeight + 1
+		if selectedLine+span > offset+maxHeight {
+			offset = selectedLine + span - maxHeight
+		}
+		if selectedLine < offset {
+			offset = selectedLine
 		}
 	}
 
@@ -1340,13 +1827,7 @@ func (m Model) scrollRows(rows []string, selectedLine int, maxHeight int) []stri
 	if offset > maxOffset {
 		offset = maxOffset
 	}
-
-	end := offset + maxHeight
-	if end > len(rows) {
-		end = len(rows)
-	}
-
-	return rows[offset:end]
+	return offset
 }
 
 func (m Model) renderTaskRow(task Task, cursor bool, maxWidth int, isOverdue bool, checked bool) string {
@@ -1358,6 +1839,12 @@ func (m Model) renderTaskRow(task Task, cursor bool, maxWidth int, isOverdue boo
 	} else if task.Cancelled {
 		bullet = "✕"
 		bulletColor = lipgloss.Color(m.cfg.Theme.Overdue)
+	} else if task.Status == StatusInProgress {
+		bullet = "◐"
+		bulletColor = lipgloss.Color(m.cfg.Theme.Today)
+	} else if task.Status == StatusBlocked {
+		bullet = "⊘"
+		bulletColor = lipgloss.Color(m.cfg.Theme.Overdue)
 	}
 	if isOverdue {
 		bulletColor = lipgloss.Color(m.cfg.Theme.Overdue)
@@ -1372,13 +1859,6 @@ func (m Model) renderTaskRow(task Task, cursor bool, maxWidth int, isOverdue boo
 		Foreground(bulletColor)
 
 	desc := task.Description
-	descMaxWidth := maxWidth - 8
-	if descMaxWidth < 10 {
-		descMaxWidth = 10
-	}
-	if len(desc) > descMaxWidth {
-		desc = desc[:descMaxWidth-3] + "..."
-	}
 
 	descStyle := lipgloss.NewStyle().PaddingLeft(1)
 	if task.IsCompleted() {
@@ -1405,13 +1885,15 @@ func (m Model) renderTaskRow(task Task, cursor bool, maxWidth int, isOverdue boo
 		priorityStr = " " + emoji
 	}
 
-	line := prefix + bulletStyle.Render(bullet) + descStyle.Render(desc)
-	if priorityStr != "" {
-		line += priorityStr
-	}
+	suffix := priorityStr
 	if tagStr != "" {
-		line += " " + tagStr
+		suffix += " " + tagStr
+	}
+	// The cursor's left border takes a column of its own.
+	if cursor {
+		maxWidth--
 	}
+	line := m.layoutTaskLine(prefix+bulletStyle.Render(bullet), desc, descStyle, suffix, maxWidth)
 
 	rowStyle := lipgloss.NewStyle().Width(maxWidth)
 	if cursor {
@@ -1425,6 +1907,39 @@ func (m Model) renderTaskRow(task Task, cursor bool, maxWidth int, isOverdue boo
 	return rowStyle.Render(line)
 }
 
+// layoutTaskLine fits a task row's bullet, description and trailing
+// priority and tags into maxWidth columns. The description is truncated to
+// one line, leaving room for the priority and tags, or with soft wrap on,
+// continues on lines indented beneath it.
+func (m Model) layoutTaskLine(lead, desc string, descStyle lipgloss.Style, suffix string, maxWidth int) string {
+	if !m.softWrap {
+		desc = truncateText(desc, max(10, maxWidth-lipgloss.Width(lead)-1-lipgloss.Width(suffix)))
+		return truncateText(lead+descStyle.Render(desc)+suffix, maxWidth)
+	}
+
+	indent := strings.Repeat(" ", lipgloss.Width(lead))
+	var lines []string
+	for i, part := range wrapText(desc, max(10, maxWidth-lipgloss.Width(lead)-1)) {
+		if i == 0 {
+			lines = append(lines, lead+descStyle.Render(part))
+		} else {
+			lines = append(lines, indent+descStyle.Render(part))
+		}
+	}
+	if suffix != "" {
+		last := len(lines) - 1
+		if lipgloss.Width(lines[last])+lipgloss.Width(suffix) <= maxWidth {
+			lines[last] += suffix
+		} else {
+			lines = append(lines, indent+suffix)
+		}
+	}
+	for i, line := range lines {
+		lines[i] = truncateText(line, maxWidth)
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (m Model) renderLogbookTaskRow(task Task, selected bool, maxWidth int) string {
 	bullet := "●"
 	bulletColor := lipgloss.Color(m.cfg.Theme.Muted)
@@ -1440,13 +1955,6 @@ func (m Model) renderLogbookTaskRow(task Task, selected bool, maxWidth int) stri
 		PaddingLeft(2)
 
 	desc := task.Description
-	descMaxWidth := maxWidth - 8
-	if descMaxWidth < 10 {
-		descMaxWidth = 10
-	}
-	if len(desc) > descMaxWidth {
-		desc = desc[:descMaxWidth-3] + "..."
-	}
 
 	descStyle := lipgloss.NewStyle().
 		PaddingLeft(1).
@@ -1460,10 +1968,14 @@ func (m Model) renderLogbookTaskRow(task Task, selected bool, maxWidth int) stri
 	}
 	tagStr := strings.Join(tagParts, " ")
 
-	line := bulletStyle.Render(bullet) + descStyle.Render(desc)
+	suffix := ""
 	if tagStr != "" {
-		line += " " + tagStr
+		suffix = " " + tagStr
+	}
+	if selected {
+		maxWidth--
 	}
+	line := m.layoutTaskLine(bulletStyle.Render(bullet), desc, descStyle, suffix, maxWidth)
 
 	rowStyle := lipgloss.NewStyle().Width(maxWidth)
 	if selected {
@@ -1486,17 +1998,96 @@ func (m Model) renderFooter(width int) string {
 		statusPart = statusStyle.Render(" "+m.statusMsg) + "  "
 	}
 
+	km := m.keymap()
 	var keys string
-	if len(m.selected) > 0 {
-		keys = fmt.Sprintf("(%d selected) d done  s reschedule  v toggle all  esc clear  ? help  q quit", len(m.selected))
+	if len(m.pendingKeys) > 0 {
+		keys = formatKeySequence(m.pendingKeys) + "…"
+	} else if len(m.selected) > 0 {
+		mode := ""
+		if m.visual {
+			mode = "-- VISUAL -- "
+		}
+		keys = mode + fmt.Sprintf("(%d selected) ", len(m.selected)) + strings.Join([]string{
+			km.hint("done", "done"),
+			km.hint("reschedule", "reschedule"),
+			km.hint("priority", "priority"),
+			km.hint("follow-up", "follow_up"),
+			km.hint("cancel", "cancel"),
+			km.hint("tags", "tag_add", "tag_remove"),
+			km.hint("toggle all", "select_all"),
+			km.hint("visual", "visual"),
+			km.hint("clear", "clear"),
+			km.hint("help", "help"),
+			km.hint("quit", "quit"),
+		}, "  ")
 	} else if m.activeView == viewLogbook {
-		keys = "←/→ prev/next day  d reopen  / filter  ? help  q quit"
+		keys = strings.Join([]string{
+			km.hint("prev/next day", "prev_day", "next_day"),
+			km.hint("reopen", "done"),
+			km.hint("filter", "filter"),
+			km.hint("help", "help"),
+			km.hint("quit", "quit"),
+		}, "  ")
+	} else if m.activeView == viewCalendar {
+		keys = m.calendarFooterKeys()
+	} else if m.activeView == viewWeek {
+		keys = strings.Join([]string{
+			km.hint("column", "left", "right"),
+			km.hint("move", "down", "up"),
+			km.hint("move task", "move_left", "move_right"),
+			km.hint("new", "new"),
+			km.hint("done", "done"),
+			km.hint("reschedule", "reschedule"),
+			km.hint("select", "select"),
+			km.hint("help", "help"),
+			km.hint("quit", "quit"),
+		}, "  ")
+	} else if m.activeView == viewInbox {
+		keys = strings.Join([]string{
+			km.hint("new", "new"),
+			km.hint("triage", "triage"),
+			km.hint("done", "done"),
+			km.hint("reschedule", "reschedule"),
+			km.hint("priority", "priority"),
+			km.hint("edit", "edit"),
+			km.hint("tags", "tag_add", "tag_remove"),
+			km.hint("select", "select"),
+			km.hint("filter", "filter"),
+			km.hint("help", "help"),
+		}, "  ")
+	} else if m.activeView == viewBoard {
+		keys = strings.Join([]string{
+			km.hint("column", "left", "right"),
+			km.hint("move", "down", "up"),
+			km.hint("change status", "move_left", "move_right"),
+			km.hint("done", "done"),
+			km.hint("edit", "edit"),
+			km.hint("select", "select"),
+			km.hint("help", "help"),
+			km.hint("quit", "quit"),
+		}, "  ")
 	} else {
 		toggleState := "off"
 		if m.showPrioritySeparators {
 			toggleState = "on"
 		}
-		keys = fmt.Sprintf("n new  d done  f follow-up  s reschedule  p priority  e edit  D cancel  t separators(%s)  space select  v all  / filter  ? help", toggleState)
+		keys = strings.Join([]string{
+			km.hint("new", "new"),
+			km.hint("done", "done"),
+			km.hint("follow-up", "follow_up"),
+			km.hint("reschedule", "reschedule"),
+			km.hint("priority", "priority"),
+			km.hint("edit", "edit"),
+			km.hint("form", "edit_form"),
+			km.hint("cancel", "cancel"),
+			km.hint("tags", "tag_add", "tag_remove"),
+			km.hint("details", "details"),
+			km.hint("separators("+toggleState+")", "separators"),
+			km.hint("select", "select"),
+			km.hint("all", "select_all"),
+			km.hint("filter", "filter"),
+			km.hint("help", "help"),
+		}, "  ")
 	}
 
 	keyStyle := lipgloss.NewStyle().
@@ -1514,42 +2105,7 @@ func (m Model) renderFooter(width int) string {
 }
 
 func (m Model) renderHelp() string {
-	helpText := `
-  Obsidian Tasks TUI
-
-  Navigation
-    j/k  ↑/↓       Move up/down
-    h/l             Sidebar / Content
-    Tab             Toggle focus
-    1/2/3           Today / Upcoming / Logbook
-    ←/→             Logbook: prev/next day
-    Enter           Toggle done
-
-  Actions
-    n               New task
-    e               Edit task
-    d               Toggle done/reopen
-    f               Create follow-up for tomorrow
-    s               Reschedule task
-    p               Set priority
-    t               Toggle priority separators
-    D               Cancel task
-    /               Filter by text
-    r               Reload from files
-
-  Sync
-    Auto-sync       Reloads when daily note files change
-    r               Manual fallback reload
-
-  Bulk Selection
-    Space           Toggle select
-    v               Select/deselect all
-    d               Mark selected done
-    s               Reschedule selected
-    Esc             Clear selection
-
-  Press any key to close.
-`
+	helpText := m.keymap().helpText()
 	style := lipgloss.NewStyle().
 		Border(subtleBorder).
 		BorderForeground(lipgloss.Color(m.cfg.Theme.Accent)).
@@ -1560,100 +2116,3 @@ func (m Model) renderHelp() string {
 		lipgloss.Center, lipgloss.Center,
 		style.Render(helpText))
 }
-
-func parseRelativeDate(input string) (time.Time, error) {
-	input = strings.TrimSpace(strings.ToLower(input))
-	now := time.Now()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-
-	if input == "" {
-		return time.Time{}, fmt.Errorf("empty date input")
-	}
-
-	if t, err := time.Parse("2006-01-02", input); err == nil {
-		return t, nil
-	}
-
-	if t, err := time.Parse("01/02", input); err == nil {
-		result := time.Date(today.Year(), t.Month(), t.Day(), 0, 0, 0, 0, today.Location())
-		if result.Before(today) {
-			result = result.AddDate(1, 0, 0)
-		}
-		return result, nil
-	}
-
-	if t, err := time.Parse("Jan 02", input); err == nil {
-		result := time.Date(today.Year(), t.Month(), t.Day(), 0, 0, 0, 0, today.Location())
-		if result.Before(today) {
-			result = result.AddDate(1, 0, 0)
-		}
-		return result, nil
-	}
-
-	if t, err := time.Parse("Jan 2", input); err == nil {
-		result := time.Date(today.Year(), t.Month(), t.Day(), 0, 0, 0, 0, today.Location())
-		if result.Before(today) {
-			result = result.AddDate(1, 0, 0)
-		}
-		return result, nil
-	}
-
-	switch input {
-	case "today", "tod":
-		return today, nil
-	case "tomorrow", "tmr", "tom":
-		return today.AddDate(0, 0, 1), nil
-	case "yesterday":
-		return today.AddDate(0, 0, -1), nil
-	case "next week", "nw":
-		d := today.AddDate(0, 0, 1)
-		for d.Weekday() != time.Monday {
-			d = d.AddDate(0, 0, 1)
-		}
-		return d, nil
-	}
-
-	days := map[string]time.Weekday{
-		"mon": time.Monday, "monday": time.Monday,
-		"tue": time.Tuesday, "tuesday": time.Tuesday,
-		"wed": time.Wednesday, "wednesday": time.Wednesday,
-		"thu": time.Thursday, "thursday": time.Thursday,
-		"fri": time.Friday, "friday": time.Friday,
-		"sat": time.Saturday, "saturday": time.Saturday,
-		"sun": time.Sunday, "sunday": time.Sunday,
-	}
-	if wd, ok := days[input]; ok {
-		d := today.AddDate(0, 0, 1)
-		for d.Weekday() != wd {
-			d = d.AddDate(0, 0, 1)
-		}
-		return d, nil
-	}
-
-	offsetStr := input
-	if strings.HasPrefix(offsetStr, "+") {
-		offsetStr = offsetStr[1:]
-	}
-	if len(offsetStr) >= 2 {
-		unit := offsetStr[len(offsetStr)-1]
-		numStr := offsetStr[:len(offsetStr)-1]
-		var n int
-		if _, err := fmt.Sscanf(numStr, "%d", &n); err == nil {
-			switch unit {
-			case 'd':
-				return today.AddDate(0, 0, n), nil
-			case 'w':
-				return today.AddDate(0, 0, n*7), nil
-			case 'm':
-				return today.AddDate(0, n, 0), nil
-			}
-		}
-	}
-
-	var plainDays int
-	if _, err := fmt.Sscanf(input, "%d", &plainDays); err == nil {
-		return today.AddDate(0, 0, plainDays), nil
-	}
-
-	return time.Time{}, fmt.Errorf("unrecognized date: %s", input)
-}
@@ -5,11 +5,16 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/muesli/termenv"
 )
 
@@ -307,6 +312,1231 @@ func TestRenderTodayViewOverdueUsesLocalDateNotTimezone(t *testing.T) {
 	}
 }
 
+func TestCalendarPickCellPrefillsReschedule(t *testing.T) {
+	cfg := testConfigWithTempVault(t)
+	today := localToday()
+	writeDailyNote(t, cfg, today, []string{
+		"- [ ] Plan sprint 📅 " + today.Format("2006-01-02"),
+	})
+
+	tasks, err := ScanDailyNotes(cfg)
+	if err != nil {
+		t.Fatalf("scan daily notes: %v", err)
+	}
+
+	m := Model{
+		cfg:      cfg,
+		allTasks: tasks,
+		selected: make(map[int]bool),
+		input:    textinput.New(),
+		focus:    focusContent,
+	}
+	m.buildViews()
+	m.setActiveView(viewCalendar)
+
+	if count := m.viewTaskCount(viewCalendar); count != 1 {
+		t.Fatalf("expected 1 open task on today's cell, got %d", count)
+	}
+
+	for _, key := range []string{"enter", "s", "l", "l", "enter"} {
+		updated, _ := m.Update(keyMsg(key))
+		m = updated.(Model)
+	}
+
+	target := today.AddDate(0, 0, 2)
+	if m.mode != modeReschedule {
+		t.Fatalf("expected reschedule input after picking a cell, got mode %d", m.mode)
+	}
+	if m.input.Value() != target.Format("2006-01-02") {
+		t.Fatalf("expected picked date to be pre-filled, got %q", m.input.Value())
+	}
+
+	updated, _ := m.Update(keyMsg("enter"))
+	m = updated.(Model)
+
+	if len(m.allTasks) != 1 || !sameDay(m.allTasks[0].DueDate, target) {
+		t.Fatalf("expected task to move to %s, got %+v", target.Format("2006-01-02"), m.allTasks)
+	}
+	if !m.calendarPickFrom.IsZero() {
+		t.Fatalf("expected pick state to be cleared after rescheduling")
+	}
+}
+
+func TestWeekBoardShiftMovesTaskToNextDay(t *testing.T) {
+	cfg := testConfigWithTempVault(t)
+	today := localToday()
+	// Start the week today so tomorrow is on the board whatever the day.
+	cfg.Tasks.WeekStart = strings.ToLower(today.Weekday().String())
+	writeDailyNote(t, cfg, today, []string{
+		"- [ ] Draft agenda 📅 " + today.Format("2006-01-02"),
+	})
+
+	tasks, err := ScanDailyNotes(cfg)
+	if err != nil {
+		t.Fatalf("scan daily notes: %v", err)
+	}
+
+	m := Model{
+		cfg:      cfg,
+		allTasks: tasks,
+		selected: make(map[int]bool),
+		focus:    focusContent,
+	}
+	m.buildViews()
+	m.setActiveView(viewWeek)
+
+	updated, _ := m.Update(keyMsg("L"))
+	m = updated.(Model)
+
+	tomorrow := today.AddDate(0, 0, 1)
+	if !sameDay(m.allTasks[0].DueDate, tomorrow) {
+		t.Fatalf("expected task to move to %s, got %s", tomorrow.Format("2006-01-02"), m.allTasks[0].DueDate.Format("2006-01-02"))
+	}
+	if m.boardCol != 1 {
+		t.Fatalf("expected cursor to follow the task to column 1, got %d", m.boardCol)
+	}
+	if task := m.selectedTask(); task == nil || task.Description != "Draft agenda" {
+		t.Fatalf("expected moved task under the cursor, got %+v", task)
+	}
+}
+
+func TestWeekBoardFlagsColumnsOverDailyLimit(t *testing.T) {
+	today := localToday()
+	m := Model{
+		cfg: DefaultConfig(),
+		allTasks: []Task{
+			{Description: "One", DueDate: today},
+			{Description: "Two", DueDate: today},
+			{Description: "Three", DueDate: today},
+		},
+		focus: focusContent,
+	}
+	m.cfg.Tasks.DailyLimit = 2
+	m.buildViews()
+
+	plain := ansiRE.ReplaceAllString(m.renderWeekView(160, 20), "")
+	if !strings.Contains(plain, "3/2 !") {
+		t.Fatalf("expected today's column to be flagged over capacity:\n%s", plain)
+	}
+	if !strings.Contains(plain, "Someday") {
+		t.Fatalf("expected a Someday column:\n%s", plain)
+	}
+}
+
+func TestWeekBoardFollowsWeekStartAndSomedayClearsDates(t *testing.T) {
+	cfg := testConfigWithTempVault(t)
+	cfg.Vault.InboxFile = "Inbox"
+	today := localToday()
+	// Start the week yesterday, so the board opens on its second column.
+	cfg.Tasks.WeekStart = strings.ToLower(today.AddDate(0, 0, -1).Weekday().String())
+	later := today.Format("2006-01-02")
+	writeDailyNote(t, cfg, today, []string{"- [ ] Daily errand 📅 " + later})
+	inboxPath := filepath.Join(cfg.Vault.Path, "Inbox.md")
+	if err := os.WriteFile(inboxPath, []byte("- [ ] Inbox errand 📅 "+later+"\n- [ ] Someday idea\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tasks, err := ScanDailyNotes(cfg)
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	m := Model{cfg: cfg, allTasks: tasks, selected: make(map[int]bool), focus: focusContent}
+	m.buildViews()
+	m.setActiveView(viewWeek)
+
+	cols := m.weekColumns()
+	if !sameDay(cols[0].Date, today.AddDate(0, 0, -1)) || cols[1].Title != "Today" || m.boardCol != 1 {
+		t.Fatalf("expected the week to start yesterday and open on today, got %q %q col %d", cols[0].Title, cols[1].Title, m.boardCol)
+	}
+	someday := cols[7]
+	if !someday.Someday || len(someday.Tasks) != 1 || m.allTasks[someday.Tasks[0]].Description != "Someday idea" {
+		t.Fatalf("expected undated tasks in Someday, got %+v", someday)
+	}
+
+	m = m.moveToWeekColumn(0)
+	if m.statusMsg != "Can't reschedule into the past" {
+		t.Fatalf("expected moving into yesterday to be refused, got %q", m.statusMsg)
+	}
+
+	for i := range m.weekColumns()[1].Tasks {
+		m.selected[m.weekColumns()[1].Tasks[i]] = true
+	}
+	m = m.moveToWeekColumn(7)
+	inbox, _ := os.ReadFile(inboxPath)
+	if string(inbox) != "- [ ] Inbox errand\n- [ ] Someday idea\n" {
+		t.Fatalf("expected the inbox task's date to be cleared, got:\n%s", inbox)
+	}
+	daily, _ := os.ReadFile(filepath.Join(cfg.Vault.Path, cfg.Vault.DailyNotesDir, dailyNoteName(cfg, today)))
+	if !strings.Contains(string(daily), "Daily errand 📅 "+later) {
+		t.Fatalf("expected the daily note task to keep its date, got:\n%s", daily)
+	}
+	if !strings.Contains(m.statusMsg, "1 tasks → Someday") {
+		t.Fatalf("unexpected status %q", m.statusMsg)
+	}
+}
+
+func TestWeekViewShowsWeeklyAndMonthlyNoteTasks(t *testing.T) {
+	cfg := testConfigWithTempVault(t)
+	cfg.Vault.WeeklyNotesDir = "Weekly"
+	cfg.Vault.WeeklyNoteFormat = "gggg-[W]ww"
+	cfg.Vault.MonthlyNotesDir = "Monthly"
+	cfg.Vault.MonthlyNoteFormat = "YYYY-MM"
+	today := localToday()
+	tomorrow := today.AddDate(0, 0, 1)
+	cfg.Tasks.WeekStart = strings.ToLower(today.Weekday().String())
+
+	writePeriodic := func(source periodicSource, day time.Time, lines ...string) {
+		t.Helper()
+		path, err := source.notePath(cfg, source.periodAt(day))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sources := cfg.periodicSources()
+	weekly, monthly := sources[0], sources[1]
+	writePeriodic(weekly, today, "## Open Space", "- [ ] Plan sprint", "- [ ] Ship release 📅 "+tomorrow.Format("2006-01-02"))
+	writePeriodic(weekly, today.AddDate(0, 0, -7), "## Open Space", "- [ ] Leftover")
+	writePeriodic(monthly, today, "## Open Space", "- [ ] Budget review")
+
+	tasks, err := ScanDailyNotes(cfg)
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	m := Model{cfg: cfg, allTasks: tasks, selected: make(map[int]bool), focus: focusContent}
+	m.buildViews()
+
+	names := func(indices []int) string {
+		var out []string
+		for _, idx := range indices {
+			out = append(out, m.allTasks[idx].Description)
+		}
+		return strings.Join(out, ",")
+	}
+	if got := names(m.todayTasks); got != "Leftover" {
+		t.Fatalf("expected only last week's task to be overdue today, got %q", got)
+	}
+	cols := m.weekColumns()
+	if got := names(cols[1].Tasks); got != "Ship release" {
+		t.Fatalf("expected the dated weekly task on tomorrow's column, got %q", got)
+	}
+	week, month := cols[len(cols)-2], cols[len(cols)-1]
+	if week.Title != "This week" || names(week.Tasks) != "Plan sprint" {
+		t.Fatalf("unexpected week bucket %q: %q", week.Title, names(week.Tasks))
+	}
+	if month.Title != "This month" || names(month.Tasks) != "Budget review" {
+		t.Fatalf("unexpected month bucket %q: %q", month.Title, names(month.Tasks))
+	}
+
+	plan := m.allTasks[week.Tasks[0]]
+	if got := formatTaskLine(plan); got != "- [ ] Plan sprint" {
+		t.Fatalf("expected an undated weekly task to stay undated, got %q", got)
+	}
+
+	m.setActiveView(viewWeek)
+	m.boardCol = 1
+	m.contentCursor = 0
+	m = m.moveToWeekColumn(len(cols) - 2)
+	if m.statusMsg == "" || !sameDay(m.allTasks[cols[1].Tasks[0]].DueDate, tomorrow) {
+		t.Fatalf("expected moving into This week to be refused, got %q", m.statusMsg)
+	}
+}
+
+func TestInboxHoldsUndatedTasksAndTriagesThem(t *testing.T) {
+	cfg := testConfigWithTempVault(t)
+	cfg.Vault.InboxFile = "Inbox"
+	today := localToday()
+	tomorrow := today.AddDate(0, 0, 1)
+	inboxPath := filepath.Join(cfg.Vault.Path, "Inbox.md")
+	inbox := "# Inbox\n- [ ] Buy stamps\n- [ ] Call plumber\n- [ ] Pay rent 📅 " + today.Format("2006-01-02") + "\n"
+	if err := os.WriteFile(inboxPath, []byte(inbox), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tasks, err := ScanDailyNotes(cfg)
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	m := Model{cfg: cfg, allTasks: tasks, selected: make(map[int]bool), focus: focusContent, input: textinput.New()}
+	m.buildViews()
+	names := func(indices []int) string {
+		var out []string
+		for _, idx := range indices {
+			out = append(out, m.allTasks[idx].Description)
+		}
+		return strings.Join(out, ",")
+	}
+	if got := names(m.inboxTasks); got != "Buy stamps,Call plumber" {
+		t.Fatalf("unexpected inbox %q", got)
+	}
+	if got := names(m.todayTasks); got != "Pay rent" {
+		t.Fatalf("expected the dated inbox task in Today, got %q", got)
+	}
+
+	// Without a date, quick-add goes to the inbox; with one, to its note.
+	m = m.createQuickAddTask("Renew passport !")
+	m = m.createQuickAddTask("Dentist ^tomorrow")
+	content, _ := os.ReadFile(inboxPath)
+	if !strings.HasSuffix(string(content), "- [ ] Renew passport 🔼\n") || strings.Contains(string(content), "Dentist") {
+		t.Fatalf("expected only the undated task appended to the inbox:\n%s", content)
+	}
+	if got := names(m.inboxTasks); got != "Renew passport,Buy stamps,Call plumber" {
+		t.Fatalf("unexpected inbox after quick-add %q", got)
+	}
+
+	answer := func(value string) {
+		t.Helper()
+		m.input.SetValue(value)
+		updated, _ := m.Update(keyMsg("enter"))
+		m = updated.(Model)
+	}
+	m.setActiveView(viewToday)
+	updated, _ := m.runAction("triage")
+	m = updated.(Model)
+	if m.mode != modeTriage || m.activeView != viewInbox || m.selectedTask().Description != "Renew passport" {
+		t.Fatalf("expected triage to start on the first inbox task, got mode %d view %d", m.mode, m.activeView)
+	}
+	answer("tomorrow !!")
+	if m.selectedTask().Description != "Buy stamps" {
+		t.Fatalf("expected triage to move on, at %q", m.selectedTask().Description)
+	}
+	answer("")
+	answer("soonish")
+	if m.mode != modeTriage || !strings.Contains(m.statusMsg, "soonish") || m.selectedTask().Description != "Call plumber" {
+		t.Fatalf("expected an unreadable answer to be reported and kept, got %q", m.statusMsg)
+	}
+	answer("#errand")
+	if m.mode != modeNormal || m.statusMsg != "Inbox triaged" {
+		t.Fatalf("expected triage to finish, got mode %d %q", m.mode, m.statusMsg)
+	}
+
+	content, _ = os.ReadFile(inboxPath)
+	for _, want := range []string{
+		"- [ ] Buy stamps\n",
+		"- [ ] Call plumber #errand\n",
+		"- [ ] Renew passport ⏫ 📅 " + tomorrow.Format("2006-01-02") + "\n",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Fatalf("expected %q in the inbox:\n%s", want, content)
+		}
+	}
+	if got := names(m.inboxTasks); got != "Buy stamps,Call plumber" {
+		t.Fatalf("expected the dated task to leave the inbox, got %q", got)
+	}
+}
+
+func TestBoardShiftMovesCardToInProgress(t *testing.T) {
+	cfg := testConfigWithTempVault(t)
+	today := localToday()
+	writeDailyNote(t, cfg, today, []string{
+		"- [ ] Write tests 📅 " + today.Format("2006-01-02"),
+	})
+
+	tasks, err := ScanDailyNotes(cfg)
+	if err != nil {
+		t.Fatalf("scan daily notes: %v", err)
+	}
+
+	m := Model{
+		cfg:      cfg,
+		allTasks: tasks,
+		selected: make(map[int]bool),
+		focus:    focusContent,
+	}
+	m.buildViews()
+	m.setActiveView(viewBoard)
+
+	updated, _ := m.Update(keyMsg("L"))
+	m = updated.(Model)
+
+	if m.allTasks[0].Status != StatusInProgress {
+		t.Fatalf("expected task to be in progress, got status %q", m.allTasks[0].Status)
+	}
+	if m.boardCol != 1 || m.viewTaskCount(viewBoard) != 1 {
+		t.Fatalf("expected cursor in the in-progress column, got column %d", m.boardCol)
+	}
+}
+
+func TestDetailPaneShowsFieldsAndContext(t *testing.T) {
+	cfg := testConfigWithTempVault(t)
+	today := localToday()
+	writeDailyNote(t, cfg, today, []string{
+		"- [/] Prepare launch #work ⏫ 📅 " + today.Format("2006-01-02"),
+		"    - check landing page copy",
+		"Notes about the launch",
+	})
+
+	tasks, err := ScanDailyNotes(cfg)
+	if err != nil {
+		t.Fatalf("scan daily notes: %v", err)
+	}
+
+	m := Model{
+		cfg:        cfg,
+		allTasks:   tasks,
+		selected:   make(map[int]bool),
+		focus:      focusContent,
+		showDetail: true,
+	}
+	m.buildViews()
+	m = m.loadDetailContext()
+
+	plain := ansiRE.ReplaceAllString(m.renderDetail(60, 30), "")
+	for _, want := range []string{
+		"Prepare launch",
+		"In progress",
+		"⏫ High",
+		"#work",
+		filepath.Join("daily", today.Format("2006-01-02")+".md"),
+		"## Open Space",
+		"check landing page copy",
+		"Notes about the launch",
+	} {
+		if !strings.Contains(plain, want) {
+			t.Fatalf("expected detail pane to contain %q, got:\n%s", want, plain)
+		}
+	}
+
+	// Rendering uses the context loaded with the selection, not the note.
+	notePath := tasks[0].FilePath
+	if err := os.WriteFile(notePath, []byte("- [/] Prepare launch\n"), 0o644); err != nil {
+		t.Fatalf("rewrite note: %v", err)
+	}
+	plain = ansiRE.ReplaceAllString(m.renderDetail(60, 30), "")
+	if !strings.Contains(plain, "Notes about the launch") {
+		t.Fatalf("expected the cached context, got:\n%s", plain)
+	}
+	updated, _ := m.Update(keyMsg("r"))
+	m = updated.(Model)
+	plain = ansiRE.ReplaceAllString(m.renderDetail(60, 30), "")
+	if strings.Contains(plain, "Notes about the launch") {
+		t.Fatalf("expected reload to refresh the context, got:\n%s", plain)
+	}
+}
+
+func TestEditFormRewritesAllFieldsInOneWrite(t *testing.T) {
+	cfg := testConfigWithTempVault(t)
+	today := localToday()
+	notePath := writeDailyNote(t, cfg, today, []string{
+		"- [ ] Old title #work 📅 " + today.Format("2006-01-02"),
+	})
+
+	tasks, err := ScanDailyNotes(cfg)
+	if err != nil {
+		t.Fatalf("scan daily notes: %v", err)
+	}
+
+	m := Model{
+		cfg:      cfg,
+		allTasks: tasks,
+		selected: make(map[int]bool),
+		focus:    focusContent,
+	}
+	m.buildViews()
+
+	m, _ = m.openEditForm(*m.selectedTask())
+	m.form[formDescription].SetValue("New title")
+	m.form[formTags].SetValue("work ops")
+	m.form[formPriority].SetValue("high")
+	m.form[formScheduled].SetValue("not a date")
+
+	updated, _ := m.submitForm()
+	m = updated.(Model)
+	if m.mode != modeForm || m.formFocus != formScheduled || m.formErr == "" {
+		t.Fatalf("expected validation error on the scheduled field, got mode %d focus %d err %q", m.mode, m.formFocus, m.formErr)
+	}
+
+	m.form[formScheduled].SetValue("+1d")
+	m.form[formRecurrence].SetValue("every day")
+	updated, _ = m.submitForm()
+	m = updated.(Model)
+	if m.mode != modeNormal {
+		t.Fatalf("expected form to close after a valid submit, got error %q", m.formErr)
+	}
+
+	content, err := os.ReadFile(notePath)
+	if err != nil {
+		t.Fatalf("read note: %v", err)
+	}
+	expected := "- [ ] New title #work #ops ⏫ 🔁 every day ⏳ " + today.AddDate(0, 0, 1).Format("2006-01-02") + " 📅 " + today.Format("2006-01-02")
+	if !strings.Contains(string(content), expected) {
+		t.Fatalf("expected rebuilt line %q, got:\n%s", expected, content)
+	}
+}
+
+func TestEditFormRefusesToClearADailyNoteDueDate(t *testing.T) {
+	cfg := testConfigWithTempVault(t)
+	cfg.Vault.InboxFile = "Inbox"
+	today := localToday()
+	due := " 📅 " + today.Format("2006-01-02")
+	notePath := writeDailyNote(t, cfg, today, []string{"- [ ] Daily errand" + due})
+	inboxPath := filepath.Join(cfg.Vault.Path, "Inbox.md")
+	if err := os.WriteFile(inboxPath, []byte("- [ ] Inbox errand"+due+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tasks, err := ScanDailyNotes(cfg)
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	m := Model{cfg: cfg, allTasks: tasks, selected: make(map[int]bool), focus: focusContent}
+	m.buildViews()
+
+	submit := func(description string) {
+		t.Helper()
+		m.contentCursor = slices.IndexFunc(m.currentViewTasks(), func(i int) bool {
+			return m.allTasks[i].Description == description
+		})
+		m, _ = m.openEditForm(*m.selectedTask())
+		m.form[formDue].SetValue("")
+		updated, _ := m.submitForm()
+		m = updated.(Model)
+	}
+
+	submit("Daily errand")
+	if m.mode != modeForm || m.formFocus != formDue || !strings.Contains(m.formErr, "due with their note") {
+		t.Fatalf("expected clearing Due to be refused, got mode %d focus %d err %q", m.mode, m.formFocus, m.formErr)
+	}
+	if content, _ := os.ReadFile(notePath); !strings.Contains(string(content), due) {
+		t.Fatalf("expected the daily note to keep its date, got:\n%s", content)
+	}
+
+	m.mode, m.form, m.formErr = modeNormal, nil, ""
+	submit("Inbox errand")
+	if m.mode != modeNormal {
+		t.Fatalf("expected the inbox task to be saved, got error %q", m.formErr)
+	}
+	if content, _ := os.ReadFile(inboxPath); string(content) != "- [ ] Inbox errand\n" {
+		t.Fatalf("expected the inbox task to become undated, got:\n%s", content)
+	}
+}
+
+func TestParseQuickAddRecognizesInlinePhrases(t *testing.T) {
+	today := localToday()
+	nextFriday := today.AddDate(0, 0, 1)
+	for nextFriday.Weekday() != time.Friday {
+		nextFriday = nextFriday.AddDate(0, 0, 1)
+	}
+
+	parseDate := DateParser{Today: today, WeekStart: time.Monday}.Parse
+	task := parseQuickAdd("Call the bank due fri #finance !! @scheduled +2w every monday", today, parseDate)
+
+	if task.Description != "Call the bank" {
+		t.Fatalf("unexpected description: %q", task.Description)
+	}
+	if !sameDay(task.DueDate, nextFriday) {
+		t.Fatalf("expected due %s, got %s", nextFriday.Format("2006-01-02"), task.DueDate.Format("2006-01-02"))
+	}
+	if !sameDay(task.ScheduledDate, today.AddDate(0, 0, 14)) {
+		t.Fatalf("unexpected scheduled date: %s", task.ScheduledDate.Format("2006-01-02"))
+	}
+	if task.Priority != PriorityHigh {
+		t.Fatalf("expected !! to mean high priority, got %d", task.Priority)
+	}
+	if task.Recurrence != "every monday" {
+		t.Fatalf("unexpected recurrence: %q", task.Recurrence)
+	}
+	if len(task.Tags) != 1 || task.Tags[0] != "#finance" {
+		t.Fatalf("unexpected tags: %v", task.Tags)
+	}
+
+	caret := parseQuickAdd("Buy milk ^tomorrow p1", today, parseDate)
+	if caret.Description != "Buy milk" || !sameDay(caret.DueDate, today.AddDate(0, 0, 1)) || caret.Priority != PriorityHighest {
+		t.Fatalf("unexpected caret task: %+v", caret)
+	}
+
+	plain := parseQuickAdd("Review due diligence report", today, parseDate)
+	if plain.Description != "Review due diligence report" || !sameDay(plain.DueDate, today) {
+		t.Fatalf("expected words that aren't dates to stay in the description, got %+v", plain)
+	}
+}
+
+func TestQuickAddTabCompletesKnownTags(t *testing.T) {
+	m := Model{
+		cfg: DefaultConfig(),
+		allTasks: []Task{
+			{Description: "A", Tags: []string{"#kanastra/ops"}},
+			{Description: "B", Tags: []string{"#kanastra"}},
+		},
+		selected: make(map[int]bool),
+		input:    textinput.New(),
+		mode:     modeNewTask,
+	}
+	m.input.Focus()
+	m.input.SetValue("Deploy #kan")
+
+	updated, _ := m.Update(keyMsg("tab"))
+	m = updated.(Model)
+	if m.input.Value() != "Deploy #kanastra" {
+		t.Fatalf("expected first completion, got %q", m.input.Value())
+	}
+
+	updated, _ = m.Update(keyMsg("tab"))
+	m = updated.(Model)
+	if m.input.Value() != "Deploy #kanastra/ops" {
+		t.Fatalf("expected tab to cycle completions, got %q", m.input.Value())
+	}
+}
+
+func TestTagCompletionsRankByUseAndRecency(t *testing.T) {
+	today := localToday()
+	m := Model{
+		cfg: DefaultConfig(),
+		allTasks: []Task{
+			{Description: "A", Tags: []string{"#ops"}, DueDate: today.AddDate(0, 0, -60)},
+			{Description: "B", Tags: []string{"#ops"}, DueDate: today.AddDate(0, 0, -60)},
+			{Description: "C", Tags: []string{"#onboarding"}, DueDate: today},
+			{Description: "D", Tags: []string{"#kanastra/ops"}, DueDate: today.AddDate(0, 0, -90)},
+		},
+	}
+
+	got := m.tagCompletions("#o")
+	want := []string{"#onboarding", "#ops", "#kanastra/ops"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestScanVaultTagsSkipsCodeCommentsAndFrontmatter(t *testing.T) {
+	cfg := testConfigWithTempVault(t)
+	note := "---\ntags: #meta\n---\nAbout #work\n```\n#include <stdio.h>\n```\n%% #draft %%\n<!-- #hidden -->\n- [ ] Ship #work\n"
+	if err := os.WriteFile(filepath.Join(cfg.Vault.Path, "Projects.md"), []byte(note), 0o644); err != nil {
+		t.Fatalf("write note: %v", err)
+	}
+	tags, err := ScanVaultTags(cfg.Vault.Path)
+	if err != nil {
+		t.Fatalf("ScanVaultTags: %v", err)
+	}
+	if len(tags) != 1 || tags["#work"] != 2 {
+		t.Fatalf("expected only #work, twice, got %v", tags)
+	}
+}
+
+func TestNewTagWarningSuggestsExistingCasing(t *testing.T) {
+	m := Model{
+		cfg:       DefaultConfig(),
+		allTasks:  []Task{{Description: "A", Tags: []string{"#work"}}},
+		vaultTags: map[string]int{"#work": 3, "#reading": 1},
+	}
+	if warning := m.newTagWarning([]string{"#Work"}); warning != "" {
+		t.Fatalf("expected no warning when warn_new_tags is off, got %q", warning)
+	}
+
+	m.cfg.Tasks.WarnNewTags = true
+	if warning := m.newTagWarning([]string{"#reading", "#work"}); warning != "" {
+		t.Fatalf("expected known tags to pass, got %q", warning)
+	}
+	warning := m.newTagWarning([]string{"#Work"})
+	if !strings.Contains(warning, "#Work") || !strings.Contains(warning, "did you mean #work") {
+		t.Fatalf("expected casing hint, got %q", warning)
+	}
+
+	m.vaultTags["#WORK"] = 1
+	m.vaultTags["#wOrk"] = 1
+	for range 20 {
+		if warning := m.newTagWarning([]string{"#Work"}); !strings.Contains(warning, "did you mean #work?") {
+			t.Fatalf("expected the most used casing, got %q", warning)
+		}
+		if warning := m.newTagWarning([]string{"#READING"}); !strings.Contains(warning, "did you mean #reading?") {
+			t.Fatalf("expected the only casing, got %q", warning)
+		}
+	}
+}
+
+func TestConfiguredKeysDriveNormalMode(t *testing.T) {
+	today := localToday()
+	keys, err := newKeymap(map[string][]string{"down": {"n"}, "new": {"a"}, "cancel": {"dd"}})
+	if err != nil {
+		t.Fatalf("newKeymap: %v", err)
+	}
+	m := Model{
+		cfg:  DefaultConfig(),
+		keys: keys,
+		allTasks: []Task{
+			{Description: "One", DueDate: today},
+			{Description: "Two", DueDate: today},
+			{Description: "Three", DueDate: today},
+		},
+		selected: make(map[int]bool),
+		focus:    focusContent,
+	}
+	m.buildViews()
+
+	press := func(key string) {
+		t.Helper()
+		updated, _ := m.Update(keyMsg(key))
+		m = updated.(Model)
+	}
+
+	press("n")
+	press("n")
+	if m.contentCursor != 2 || m.mode != modeNormal {
+		t.Fatalf("expected remapped n to move down, cursor=%d mode=%d", m.contentCursor, m.mode)
+	}
+	press("g")
+	if m.contentCursor != 2 || len(m.pendingKeys) != 1 {
+		t.Fatalf("expected g to wait for the rest of gg, cursor=%d pending=%v", m.contentCursor, m.pendingKeys)
+	}
+	press("g")
+	if m.contentCursor != 0 || len(m.pendingKeys) != 0 {
+		t.Fatalf("expected gg to jump to the top, cursor=%d pending=%v", m.contentCursor, m.pendingKeys)
+	}
+
+	press("d")
+	press("d")
+	if m.mode != modeConfirmDelete {
+		t.Fatalf("expected dd to ask to cancel, got mode %d", m.mode)
+	}
+	press("esc")
+
+	// A binding that is also the prefix of a longer one fires on its own
+	// once the sequence times out.
+	press("d")
+	updated, _ := m.Update(keyTimeoutMsg{id: m.pendingID})
+	m = updated.(Model)
+	if len(m.pendingKeys) != 0 {
+		t.Fatalf("expected timeout to clear pending keys, got %v", m.pendingKeys)
+	}
+}
+
+func TestKeymapRejectsConflictsAndUnknownActions(t *testing.T) {
+	if _, err := newKeymap(map[string][]string{"new": {"d"}}); err == nil || !strings.Contains(err.Error(), "done") {
+		t.Fatalf("expected conflict with done, got %v", err)
+	}
+	if _, err := newKeymap(map[string][]string{"teleport": {"x"}}); err == nil {
+		t.Fatal("expected unknown action error")
+	}
+	if _, err := newKeymap(map[string][]string{"priority_high": {"n"}}); err != nil {
+		t.Fatalf("expected keys in different scopes not to conflict, got %v", err)
+	}
+	if _, err := newKeymap(map[string][]string{"confirm_yes": {"yy"}}); err == nil {
+		t.Fatal("expected a multi-key sequence in the confirm prompt to be rejected")
+	}
+	if _, err := newKeymap(map[string][]string{"new": {"ctrl+c"}}); err == nil {
+		t.Fatal("expected ctrl+c to be reserved for quitting")
+	}
+
+	km, _ := newKeymap(map[string][]string{"quit": {"Q"}})
+	m := Model{cfg: DefaultConfig(), keys: km, selected: make(map[int]bool), mode: modeFilter}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlC}); cmd == nil {
+		t.Fatal("expected ctrl+c to quit with quit rebound")
+	} else if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Fatal("expected ctrl+c to quit with quit rebound")
+	}
+
+	km, _ = newKeymap(map[string][]string{"down": {"n"}, "new": {"a"}})
+	help := km.helpText()
+	if !strings.Contains(help, "n/k") || !strings.Contains(help, "a               New task") {
+		t.Fatalf("expected help generated from bindings, got:\n%s", help)
+	}
+}
+
+func TestPaletteRunsCommandWithArgument(t *testing.T) {
+	cfg := testConfigWithTempVault(t)
+	today := localToday()
+	writeDailyNote(t, cfg, today, []string{
+		"- [ ] Send invoice 📅 " + today.Format("2006-01-02"),
+	})
+	tasks, err := ScanDailyNotes(cfg)
+	if err != nil {
+		t.Fatalf("scan daily notes: %v", err)
+	}
+
+	m := Model{
+		cfg:      cfg,
+		allTasks: tasks,
+		selected: make(map[int]bool),
+		input:    textinput.New(),
+		focus:    focusContent,
+	}
+	m.buildViews()
+
+	updated, _ := m.Update(keyMsg(":"))
+	m = updated.(Model)
+	if m.mode != modePalette {
+		t.Fatalf("expected : to open the palette, got mode %d", m.mode)
+	}
+	for _, r := range "rsch +3d" {
+		updated, _ = m.Update(keyMsg(string(r)))
+		m = updated.(Model)
+	}
+	if matches := m.paletteMatches(); len(matches) == 0 || matches[0].name != "reschedule" {
+		t.Fatalf("expected fuzzy match on reschedule, got %+v", matches)
+	}
+	updated, _ = m.Update(keyMsg("enter"))
+	m = updated.(Model)
+
+	want := today.AddDate(0, 0, 3)
+	if m.mode != modeNormal || !sameDay(m.allTasks[0].DueDate, want) {
+		t.Fatalf("expected task rescheduled to %s, got %s (mode %d)", want.Format("2006-01-02"), m.allTasks[0].DueDate.Format("2006-01-02"), m.mode)
+	}
+}
+
+func TestFuzzyScorePrefersPrefixesAndWordStarts(t *testing.T) {
+	if _, ok := fuzzyScore("xyz", "reschedule"); ok {
+		t.Fatal("expected no match")
+	}
+	prefix, _ := fuzzyScore("sel", "select")
+	scattered, _ := fuzzyScore("sel", "separators_list")
+	if prefix <= scattered {
+		t.Fatalf("expected prefix match to win, got %d vs %d", prefix, scattered)
+	}
+	wordStart, _ := fuzzyScore("vc", "view_calendar")
+	middle, _ := fuzzyScore("vc", "move_cursor")
+	if wordStart <= middle {
+		t.Fatalf("expected word-start match to win, got %d vs %d", wordStart, middle)
+	}
+}
+
+func TestRenameTagAcrossVaultWithPreview(t *testing.T) {
+	cfg := testConfigWithTempVault(t)
+	today := localToday()
+	notePath := writeDailyNote(t, cfg, today, []string{
+		"- [ ] Ship release #Work/ops 📅 " + today.Format("2006-01-02"),
+		"- [ ] Unrelated #workshop",
+	})
+	projectPath := filepath.Join(cfg.Vault.Path, "Projects.md")
+	project := "Notes about #work here, see [[#Work]] and [ops](#work)\n```\n#work in code\n```\n"
+	if err := os.WriteFile(projectPath, []byte(project), 0o644); err != nil {
+		t.Fatalf("write project note: %v", err)
+	}
+
+	m := Model{cfg: cfg, selected: make(map[int]bool)}
+	updated, cmd := m.planRename("#work", "#job")
+	m = updated.(Model)
+	if m.mode != modeNormal || cmd == nil {
+		t.Fatalf("expected the vault scan to run in the background, got mode %d", m.mode)
+	}
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	if m.mode != modeRenamePreview || len(m.renamePlan) != 2 {
+		t.Fatalf("expected a 2-line preview, got mode %d plan %+v", m.mode, m.renamePlan)
+	}
+	preview := ansiRE.ReplaceAllString(m.renderRenamePreview(), "")
+	if !strings.Contains(preview, "2 lines in 2 notes") {
+		t.Fatalf("expected summary in preview, got:\n%s", preview)
+	}
+
+	updated, _ = m.Update(keyMsg("y"))
+	m = updated.(Model)
+	if m.mode != modeNormal {
+		t.Fatalf("expected preview to close, got mode %d", m.mode)
+	}
+
+	daily, _ := os.ReadFile(notePath)
+	if !strings.Contains(string(daily), "#job/ops 📅") || !strings.Contains(string(daily), "#workshop") {
+		t.Fatalf("unexpected daily note:\n%s", daily)
+	}
+	notes, _ := os.ReadFile(projectPath)
+	if string(notes) != "Notes about #job here, see [[#Work]] and [ops](#work)\n```\n#work in code\n```\n" {
+		t.Fatalf("unexpected project note:\n%s", notes)
+	}
+}
+
+func TestRenameTagWritesNothingWhenANoteChanged(t *testing.T) {
+	cfg := testConfigWithTempVault(t)
+	writeDailyNote(t, cfg, localToday(), []string{"- [ ] Ship release #work"})
+	projectPath := filepath.Join(cfg.Vault.Path, "Projects.md")
+	if err := os.WriteFile(projectPath, []byte("Notes about #work\n"), 0o644); err != nil {
+		t.Fatalf("write project note: %v", err)
+	}
+
+	changes, err := PlanTagRename(cfg.Vault.Path, "#work", "#job")
+	if err != nil || len(changes) != 2 {
+		t.Fatalf("expected 2 planned lines, got %d (err %v)", len(changes), err)
+	}
+	stale := changes[len(changes)-1].Path
+	if err := os.WriteFile(stale, []byte("Edited meanwhile #work\n"), 0o644); err != nil {
+		t.Fatalf("edit note: %v", err)
+	}
+
+	if written, err := ApplyTagRename(changes); err == nil || written != 0 {
+		t.Fatalf("expected the stale note to stop the rename, got %d written (err %v)", written, err)
+	}
+	for _, c := range changes {
+		data, _ := os.ReadFile(c.Path)
+		if strings.Contains(string(data), "#job") {
+			t.Fatalf("expected %s untouched, got:\n%s", filepath.Base(c.Path), data)
+		}
+	}
+}
+
+func TestBulkPriorityAndCancelApplyToSelection(t *testing.T) {
+	cfg := testConfigWithTempVault(t)
+	today := localToday()
+	yesterday := today.AddDate(0, 0, -1)
+	todayPath := writeDailyNote(t, cfg, today, []string{
+		"- [ ] Send invoice 📅 " + today.Format("2006-01-02"),
+		"- [ ] Call bank 📅 " + today.Format("2006-01-02"),
+		"- [ ] Keep me 📅 " + today.Format("2006-01-02"),
+	})
+	yesterdayPath := writeDailyNote(t, cfg, yesterday, []string{
+		"- [ ] Renew domain 📅 " + yesterday.Format("2006-01-02"),
+	})
+	tasks, err := ScanDailyNotes(cfg)
+	if err != nil {
+		t.Fatalf("scan daily notes: %v", err)
+	}
+
+	m := Model{
+		cfg:      cfg,
+		allTasks: tasks,
+		selected: make(map[int]bool),
+		focus:    focusContent,
+		width:    120,
+		height:   40,
+	}
+	m.buildViews()
+	selectAll := func() {
+		for idx, task := range m.allTasks {
+			if task.Description != "Keep me" {
+				m.selected[idx] = true
+			}
+		}
+	}
+	press := func(key string) {
+		t.Helper()
+		updated, _ := m.Update(keyMsg(key))
+		m = updated.(Model)
+	}
+
+	selectAll()
+	press("p")
+	if m.mode != modePriority {
+		t.Fatalf("expected priority prompt, got mode %d", m.mode)
+	}
+	if view := ansiRE.ReplaceAllString(m.View(), ""); !strings.Contains(view, "Priority for 3 tasks across 2 notes") {
+		t.Fatalf("expected bulk priority prompt, got:\n%s", view)
+	}
+	press("2")
+	if len(m.selected) != 0 || !strings.HasPrefix(m.statusMsg, "3 tasks") {
+		t.Fatalf("expected selection cleared and bulk status, got %v %q", m.selected, m.statusMsg)
+	}
+
+	selectAll()
+	press("D")
+	if view := ansiRE.ReplaceAllString(m.View(), ""); !strings.Contains(view, "Cancel 3 tasks across 2 notes?") {
+		t.Fatalf("expected bulk cancel summary, got:\n%s", view)
+	}
+	press("y")
+	if m.mode != modeNormal || m.statusMsg != "3 tasks cancelled" {
+		t.Fatalf("expected bulk cancel, got mode %d status %q", m.mode, m.statusMsg)
+	}
+
+	content, _ := os.ReadFile(todayPath)
+	if !strings.Contains(string(content), "- [-] Send invoice ⏫") || !strings.Contains(string(content), "- [-] Call bank ⏫") || !strings.Contains(string(content), "- [ ] Keep me 📅") {
+		t.Fatalf("unexpected today note:\n%s", content)
+	}
+	content, _ = os.ReadFile(yesterdayPath)
+	if !strings.Contains(string(content), "- [-] Renew domain ⏫") {
+		t.Fatalf("unexpected yesterday note:\n%s", content)
+	}
+}
+
+func TestVisualModeSelectsRangeAcrossSeparators(t *testing.T) {
+	cfg := testConfigWithTempVault(t)
+	today := localToday()
+	due := " 📅 " + today.Format("2006-01-02")
+	notePath := writeDailyNote(t, cfg, today, []string{
+		"- [ ] Alpha ⏫" + due,
+		"- [ ] Bravo ⏫" + due,
+		"- [ ] Charlie 🔼" + due,
+		"- [ ] Delta" + due,
+		"- [ ] Echo" + due,
+	})
+	tasks, err := ScanDailyNotes(cfg)
+	if err != nil {
+		t.Fatalf("scan daily notes: %v", err)
+	}
+
+	m := Model{
+		cfg:                    cfg,
+		allTasks:               tasks,
+		selected:               make(map[int]bool),
+		focus:                  focusContent,
+		showPrioritySeparators: true,
+	}
+	m.buildViews()
+	press := func(key string) {
+		t.Helper()
+		updated, _ := m.Update(keyMsg(key))
+		m = updated.(Model)
+	}
+
+	press("j")
+	press("V")
+	press("j")
+	press("j")
+	if !m.visual || len(m.selected) != 3 {
+		t.Fatalf("expected a 3-task visual range, got visual=%v selected=%v", m.visual, m.selected)
+	}
+	press("k")
+	if len(m.selected) != 2 {
+		t.Fatalf("expected k to shrink the range, got %v", m.selected)
+	}
+	press("j")
+	press("d")
+	if m.visual || len(m.selected) != 0 {
+		t.Fatalf("expected done to end visual mode, got visual=%v selected=%v", m.visual, m.selected)
+	}
+
+	content, _ := os.ReadFile(notePath)
+	for _, want := range []string{"- [ ] Alpha", "- [x] Bravo", "- [x] Charlie", "- [x] Delta", "- [ ] Echo"} {
+		if !strings.Contains(string(content), want) {
+			t.Fatalf("expected %q in note:\n%s", want, content)
+		}
+	}
+
+	press("V")
+	press("j")
+	press("esc")
+	if m.visual || len(m.selected) != 0 {
+		t.Fatalf("expected esc to drop the range, got visual=%v selected=%v", m.visual, m.selected)
+	}
+}
+
+func TestOpenCommandsPointAtTaskLine(t *testing.T) {
+	cases := []struct {
+		editor string
+		want   string
+	}{
+		{"nvim", "nvim +12 /v/a b.md"},
+		{"/usr/bin/vim", "/usr/bin/vim +12 /v/a b.md"},
+		{"hx", "hx /v/a b.md:12"},
+		{"code --wait", "code --wait --goto /v/a b.md:12"},
+	}
+	for _, c := range cases {
+		got := strings.Join(editorArgs(strings.Fields(c.editor), "/v/a b.md", 12), " ")
+		if got != c.want {
+			t.Errorf("editorArgs(%q) = %q, want %q", c.editor, got, c.want)
+		}
+	}
+
+	cfg := DefaultConfig()
+	cfg.Vault.Path = "/home/me/My Vault"
+	task := Task{FilePath: "/home/me/My Vault/Notes/Daily Notes/2026-03-18.md", LineNumber: 7}
+	if got, want := obsidianURI(cfg, task), "obsidian://open?vault=My%20Vault&file=Notes%2FDaily%20Notes%2F2026-03-18"; got != want {
+		t.Errorf("obsidianURI = %q, want %q", got, want)
+	}
+	cfg.Open.AdvancedURI = true
+	cfg.Open.VaultName = "Work"
+	if got, want := obsidianURI(cfg, task), "obsidian://advanced-uri?vault=Work&filepath=Notes%2FDaily%20Notes%2F2026-03-18.md&line=7"; got != want {
+		t.Errorf("obsidianURI = %q, want %q", got, want)
+	}
+}
+
+func TestMouseClicksMapOntoRenderedRows(t *testing.T) {
+	cfg := testConfigWithTempVault(t)
+	today := localToday()
+	due := " 📅 " + today.Format("2006-01-02")
+	notePath := writeDailyNote(t, cfg, today, []string{
+		"- [ ] Alpha" + due,
+		"- [ ] Bravo" + due,
+		"- [ ] Charlie" + due,
+	})
+	tasks, err := ScanDailyNotes(cfg)
+	if err != nil {
+		t.Fatalf("scan daily notes: %v", err)
+	}
+
+	m := Model{
+		cfg:      cfg,
+		allTasks: tasks,
+		selected: make(map[int]bool),
+		input:    textinput.New(),
+		focus:    focusSidebar,
+		width:    120,
+		height:   30,
+	}
+	m.buildViews()
+	click := func(x, y int, button tea.MouseButton) {
+		t.Helper()
+		updated, _ := m.Update(tea.MouseMsg{X: x, Y: y, Button: button, Action: tea.MouseActionPress})
+		m = updated.(Model)
+	}
+
+	// The content pane starts after the padded sidebar and its border; the
+	// first task row sits below the title and a blank line.
+	descX, bulletX := hPad+24+1+10, hPad+24+1+2
+	click(descX, 4, tea.MouseButtonLeft)
+	if m.focus != focusContent || m.contentCursor != 1 {
+		t.Fatalf("expected click to select Bravo, focus=%d cursor=%d", m.focus, m.contentCursor)
+	}
+
+	click(descX, 3, tea.MouseButtonWheelDown)
+	if m.contentCursor != 2 {
+		t.Fatalf("expected wheel to move down, cursor=%d", m.contentCursor)
+	}
+
+	click(descX, 3, tea.MouseButtonLeft)
+	click(descX, 3, tea.MouseButtonLeft)
+	if m.mode != modeEditTask || m.input.Value() != "Alpha" {
+		t.Fatalf("expected double-click to edit Alpha, mode=%d value=%q", m.mode, m.input.Value())
+	}
+	m.mode = modeNormal
+
+	click(bulletX, 5, tea.MouseButtonLeft)
+	content, _ := os.ReadFile(notePath)
+	if !strings.Contains(string(content), "- [x] Charlie") {
+		t.Fatalf("expected bullet click to complete Charlie:\n%s", content)
+	}
+
+	click(hPad+3, 2+int(viewUpcoming), tea.MouseButtonLeft)
+	if m.activeView != viewUpcoming {
+		t.Fatalf("expected sidebar click to open Upcoming, got view %d", m.activeView)
+	}
+}
+
+func TestMouseClicksMapOntoBoardsAndTheCalendar(t *testing.T) {
+	cfg := testConfigWithTempVault(t)
+	today := localToday()
+	tomorrow := today.AddDate(0, 0, 1)
+	cfg.Tasks.WeekStart = strings.ToLower(today.Weekday().String())
+	writeDailyNote(t, cfg, today, []string{
+		"- [ ] Alpha 📅 " + today.Format("2006-01-02"),
+		"- [ ] Bravo 📅 " + today.Format("2006-01-02"),
+		"- [ ] Charlie 📅 " + tomorrow.Format("2006-01-02"),
+	})
+	tasks, err := ScanDailyNotes(cfg)
+	if err != nil {
+		t.Fatalf("scan daily notes: %v", err)
+	}
+
+	m := Model{
+		cfg:      cfg,
+		allTasks: tasks,
+		selected: make(map[int]bool),
+		input:    textinput.New(),
+		focus:    focusContent,
+		width:    120,
+		height:   30,
+	}
+	m.buildViews()
+	// locate finds text on screen, right of the sidebar, so clicks follow
+	// what was rendered.
+	locate := func(text string) (int, int) {
+		t.Helper()
+		for y, line := range strings.Split(ansi.Strip(m.View()), "\n") {
+			if i := strings.LastIndex(line, text); i >= 0 && ansi.StringWidth(line[:i]) > hPad+24 {
+				return ansi.StringWidth(line[:i]), y
+			}
+		}
+		t.Fatalf("%q is not on screen:\n%s", text, ansi.Strip(m.View()))
+		return 0, 0
+	}
+	click := func(x, y int) {
+		t.Helper()
+		updated, _ := m.Update(tea.MouseMsg{X: x, Y: y, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+		m = updated.(Model)
+	}
+
+	m.setActiveView(viewWeek)
+	click(locate("Charlie"))
+	if task := m.selectedTask(); m.boardCol != 1 || task == nil || task.Description != "Charlie" {
+		t.Fatalf("expected a click to select Charlie in tomorrow's column, col=%d cursor=%d", m.boardCol, m.contentCursor)
+	}
+	click(locate("Today"))
+	if m.activeView != viewWeek || m.boardCol != 0 || m.contentCursor != 0 {
+		t.Fatalf("expected a header click to select today's column, col=%d cursor=%d", m.boardCol, m.contentCursor)
+	}
+	x, y := locate("Bravo")
+	click(x, y)
+	click(x, y)
+	if m.contentCursor != 1 || m.mode != modeEditTask || m.input.Value() != "Bravo" {
+		t.Fatalf("expected a double-click to edit Bravo, cursor=%d mode=%d value=%q", m.contentCursor, m.mode, m.input.Value())
+	}
+	m.mode = modeNormal
+
+	m.setActiveView(viewCalendar)
+	m.focus = focusContent
+	click(locate("Bravo"))
+	if task := m.selectedTask(); !m.calendarTasks || task == nil || task.Description != "Bravo" {
+		t.Fatalf("expected a click to select Bravo in the day's list, tasks=%v cursor=%d", m.calendarTasks, m.contentCursor)
+	}
+	// The week starts today, so tomorrow sits right of today in the grid.
+	start, _ := m.calendarGrid()
+	x, headerY := locate(tomorrow.Weekday().String()[:3])
+	click(x, headerY+1+int(today.Sub(start).Hours()/24)/7)
+	if m.calendarTasks || !sameDay(m.calendarDay(), tomorrow) {
+		t.Fatalf("expected a click on the grid to move to %s, got %s tasks=%v", tomorrow.Format("Jan 02"), m.calendarDay().Format("Jan 02"), m.calendarTasks)
+	}
+}
+
+func TestTaskRowsMeasureDisplayWidth(t *testing.T) {
+	today := localToday()
+	m := Model{
+		cfg: DefaultConfig(),
+		allTasks: []Task{
+			{Description: "Revisão do orçamento anual 日本語のテキスト 🎉🎉🎉 com bastante texto", DueDate: today, Tags: []string{"#finanças"}},
+			{Description: "Short", DueDate: today},
+		},
+		selected: make(map[int]bool),
+		focus:    focusContent,
+	}
+	m.buildViews()
+
+	for _, width := range []int{20, 33, 41} {
+		for _, cursor := range []bool{false, true} {
+			row := ansiRE.ReplaceAllString(m.renderTaskRow(m.allTasks[0], cursor, width, false, false), "")
+			if !utf8.ValidString(row) {
+				t.Fatalf("width %d: row cut a character in half: %q", width, row)
+			}
+			if got := lipgloss.Width(row); got != width {
+				t.Fatalf("width %d, cursor %v: row is %d columns wide: %q", width, cursor, got, row)
+			}
+		}
+	}
+
+	// The description is cut to keep the priority and tags whole.
+	tagged := Task{Description: "Prepare the quarterly board presentation", Priority: PriorityHigh, Tags: []string{"#work/board", "#q3"}, DueDate: today}
+	row := ansiRE.ReplaceAllString(m.renderTaskRow(tagged, false, 40, false, false), "")
+	if !strings.HasSuffix(row, "… ⏫ #work/board #q3") || lipgloss.Width(row) != 40 {
+		t.Fatalf("expected the description cut before the tags, got %q", row)
+	}
+
+	m.softWrap = true
+	m.contentCursor = 0
+	row = m.renderTaskRow(m.allTasks[0], true, 30, false, false)
+	lines := strings.Split(ansiRE.ReplaceAllString(row, ""), "\n")
+	if len(lines) < 3 {
+		t.Fatalf("expected the description to wrap, got %q", lines)
+	}
+	for _, line := range lines {
+		if lipgloss.Width(line) > 30 {
+			t.Fatalf("wrapped line too wide: %q", line)
+		}
+	}
+
+	// The whole wrapped task stays in view, and the rows after it scroll
+	// by lines rather than by tasks.
+	view := ansiRE.ReplaceAllString(m.renderTodayView(30, 4), "")
+	if !strings.Contains(view, "Revisão") || !strings.Contains(view, "#finanças") {
+		t.Fatalf("expected the wrapped task in view, got:\n%s", view)
+	}
+	m.contentCursor = 1
+	view = ansiRE.ReplaceAllString(m.renderTodayView(30, 4), "")
+	if got := strings.Count(view, "\n") + 1; got != 4 || !strings.Contains(view, "Short") {
+		t.Fatalf("expected 4 lines ending with Short, got:\n%s", view)
+	}
+}
+
+func keyMsg(key string) tea.KeyMsg {
+	switch key {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	case " ":
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
+
 func sectionHasPadding(text, sectionLabel string) bool {
 	lines := strings.Split(text, "\n")
 	for i, line := range lines {
@@ -342,11 +1572,10 @@ func testConfigWithTempVault(t *testing.T) Config {
 func writeDailyNote(t *testing.T, cfg Config, day time.Time, taskLines []string) string {
 	t.Helper()
 
-	notePath := filepath.Join(
-		cfg.Vault.Path,
-		cfg.Vault.DailyNotesDir,
-		day.Format(cfg.Vault.DailyNoteFormat)+".md",
-	)
+	notePath := filepath.Join(cfg.Vault.Path, cfg.Vault.DailyNotesDir, dailyNoteName(cfg, day))
+	if err := os.MkdirAll(filepath.Dir(notePath), 0o755); err != nil {
+		t.Fatalf("mkdir daily note folder: %v", err)
+	}
 
 	lines := []string{cfg.Tasks.SectionHeading, ""}
 	lines = append(lines, taskLines...)
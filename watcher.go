@@ -1,6 +1,8 @@
 package main
 
 import (
+	"io/fs"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -20,6 +22,17 @@ type dailyNotesWatcher struct {
 
 func newDailyNotesWatcher(cfg Config) (*dailyNotesWatcher, error) {
 	dir := filepath.Join(cfg.Vault.Path, cfg.Vault.DailyNotesDir)
+	// roots maps each notes folder to how many subfolders down its format
+	// keeps notes; 2006/01/2006-01-02 keeps them two down.
+	roots := map[string]int{dir: strings.Count(filepath.ToSlash(cfg.Vault.DailyNoteFormat), "/")}
+	for _, source := range cfg.periodicSources() {
+		root := filepath.Join(cfg.Vault.Path, source.dir)
+		roots[root] = max(roots[root], strings.Count(source.format, "/"))
+	}
+	if cfg.Vault.InboxFile != "" {
+		inbox := filepath.Dir(vaultNotePath(cfg, cfg.Vault.InboxFile))
+		roots[inbox] = max(roots[inbox], 0)
+	}
 
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
@@ -30,6 +43,16 @@ func newDailyNotesWatcher(cfg Config) (*dailyNotesWatcher, error) {
 		watcher.Close()
 		return nil, err
 	}
+	for root, depth := range roots {
+		// Weekly, monthly and inbox folders are optional.
+		if root != dir && watcher.Add(root) != nil {
+			continue
+		}
+		if err := watchSubdirs(watcher, root, root, depth); err != nil {
+			watcher.Close()
+			return nil, err
+		}
+	}
 
 	w := &dailyNotesWatcher{
 		events: make(chan fileWatchMsg, 1),
@@ -63,6 +86,13 @@ func newDailyNotesWatcher(cfg Config) (*dailyNotesWatcher, error) {
 				if !ok {
 					return
 				}
+				if root, depth := rootOf(roots, event.Name); depth > 0 && event.Op&fsnotify.Create != 0 && isDir(event.Name) {
+					// Notes may land in the new folder before it is watched,
+					// so reload as well.
+					_ = watchSubdirs(watcher, root, event.Name, depth)
+					resetDebounce()
+					continue
+				}
 				if !isRelevantDailyNoteEvent(event) {
 					continue
 				}
@@ -82,6 +112,41 @@ func newDailyNotesWatcher(cfg Config) (*dailyNotesWatcher, error) {
 	return w, nil
 }
 
+// watchSubdirs adds start and the folders below it that are at most depth
+// levels under root, skipping hidden ones such as .obsidian.
+func watchSubdirs(watcher *fsnotify.Watcher, root, start string, depth int) error {
+	return filepath.WalkDir(start, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() || path == root {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(d.Name(), ".") || strings.Count(rel, string(filepath.Separator))+1 > depth {
+			return filepath.SkipDir
+		}
+		return watcher.Add(path)
+	})
+}
+
+// rootOf returns the innermost watched notes folder holding path and its
+// depth.
+func rootOf(roots map[string]int, path string) (string, int) {
+	best := ""
+	for root := range roots {
+		if strings.HasPrefix(path, root+string(filepath.Separator)) && len(root) > len(best) {
+			best = root
+		}
+	}
+	return best, roots[best]
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
 func isRelevantDailyNoteEvent(event fsnotify.Event) bool {
 	if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) == 0 {
 		return false